@@ -0,0 +1,110 @@
+package chirouter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var routedMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodOptions, http.MethodTrace, http.MethodConnect,
+}
+
+// AllowedMethods returns HTTP methods that have routes matching the path, e.g. "/users/123".
+//
+// If automatic OPTIONS responses are enabled, OPTIONS is included for any routed path.
+func (r *Wrapper) AllowedMethods(path string) []string {
+	return r.allowedMethods(r.Router, path)
+}
+
+// EnableAutoOptions makes router respond to OPTIONS requests with Allow header computed from
+// registered routes, unless there is an explicit OPTIONS route for the path.
+//
+// It must be called before adding routes.
+func (r *Wrapper) EnableAutoOptions() {
+	r.autoOptions = true
+
+	r.Router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodOptions {
+				next.ServeHTTP(rw, req)
+
+				return
+			}
+
+			routes, path := requestRoutes(req, r.Router)
+
+			if routes.Match(chi.NewRouteContext(), http.MethodOptions, path) {
+				next.ServeHTTP(rw, req)
+
+				return
+			}
+
+			methods := r.allowedMethods(routes, path)
+			if len(methods) == 0 {
+				next.ServeHTTP(rw, req)
+
+				return
+			}
+
+			rw.Header().Set("Allow", strings.Join(methods, ", "))
+			rw.WriteHeader(http.StatusNoContent)
+		})
+	})
+}
+
+// MethodNotAllowed sets a custom http.HandlerFunc for routing paths where the method is unresolved.
+//
+// Allow header with routed methods is added to response before invoking the handler.
+func (r *Wrapper) MethodNotAllowed(h http.HandlerFunc) {
+	r.Router.MethodNotAllowed(func(rw http.ResponseWriter, req *http.Request) {
+		routes, path := requestRoutes(req, r.Router)
+
+		if methods := r.allowedMethods(routes, path); len(methods) > 0 {
+			rw.Header().Set("Allow", strings.Join(methods, ", "))
+		}
+
+		h(rw, req)
+	})
+}
+
+func (r *Wrapper) allowedMethods(routes chi.Routes, path string) []string {
+	var (
+		methods    []string
+		hasOptions bool
+	)
+
+	for _, m := range routedMethods {
+		if routes.Match(chi.NewRouteContext(), m, path) {
+			methods = append(methods, m)
+
+			if m == http.MethodOptions {
+				hasOptions = true
+			}
+		}
+	}
+
+	if r.autoOptions && !hasOptions && len(methods) > 0 {
+		methods = append(methods, http.MethodOptions)
+	}
+
+	return methods
+}
+
+// requestRoutes returns root routes and full routing path of request.
+func requestRoutes(req *http.Request, fallback chi.Routes) (chi.Routes, string) {
+	routes := fallback
+
+	if rctx := chi.RouteContext(req.Context()); rctx != nil && rctx.Routes != nil {
+		routes = rctx.Routes
+	}
+
+	path := req.URL.RawPath
+	if path == "" {
+		path = req.URL.Path
+	}
+
+	return routes, path
+}
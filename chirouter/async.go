@@ -0,0 +1,34 @@
+package chirouter
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/usecase"
+)
+
+// EnableAsync makes router and its subrouters add handlers of async use cases (marked with jobs.Async)
+// with method function, for example
+//
+//	r.EnableAsync(func(r chi.Router, method, pattern string, h http.Handler) {
+//		manager.Method(r, method, pattern, h)
+//	})
+//
+// Method function receives router that handler is added to, so that job routes share its middlewares.
+// Without EnableAsync, adding a handler of async use case panics.
+//
+// It must be called before adding routes.
+func (r *Wrapper) EnableAsync(method func(r chi.Router, method, pattern string, h http.Handler)) {
+	r.asyncMethod = method
+}
+
+// isAsync checks if handler serves use case that is marked to be served in background.
+func isAsync(h http.Handler) bool {
+	var (
+		handler  *nethttp.Handler
+		hasAsync interface{ IsAsync() bool }
+	)
+
+	return nethttp.HandlerAs(h, &handler) && usecase.As(handler.UseCase(), &hasAsync) && hasAsync.IsAsync()
+}
@@ -0,0 +1,298 @@
+package chirouter
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/swaggest/refl"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/usecase"
+)
+
+// CORS configures Cross-Origin Resource Sharing for registered routes.
+//
+// Allowed methods, request headers and exposed response headers are derived
+// from routes and use case inputs and outputs.
+type CORS struct {
+	// AllowedOrigins is a list of origins allowed for cross-domain requests, "*" allows any origin.
+	// Wildcard can not be used with AllowCredentials, use AllowOriginFunc to allow dynamic origins with credentials.
+	AllowedOrigins []string
+
+	// AllowOriginFunc is an optional custom check of origin, it is used if origin is not found in AllowedOrigins.
+	AllowOriginFunc func(r *http.Request, origin string) bool
+
+	// AllowCredentials enables Access-Control-Allow-Credentials.
+	AllowCredentials bool
+
+	// AllowedHeaders is a list of additional request headers to allow, e.g. "Authorization".
+	AllowedHeaders []string
+
+	// ExposedHeaders is a list of additional response headers to expose.
+	ExposedHeaders []string
+
+	// MaxAge sets Access-Control-Max-Age of preflight response, zero value omits header.
+	MaxAge time.Duration
+}
+
+type corsHeaders struct {
+	allowed []string
+	exposed []string
+}
+
+type corsRoutes struct {
+	cfg     CORS
+	wrapper *Wrapper
+	routes  chi.Routes
+	mu      sync.RWMutex
+	headers map[string]corsHeaders
+}
+
+// EnableCORS adds Cross-Origin Resource Sharing support to the router.
+//
+// It must be called before adding routes, preflight requests are served
+// before routing and do not clash with OPTIONS routes of the router.
+//
+// It panics if wildcard origin is allowed with credentials, as it is forbidden by Fetch standard.
+func (r *Wrapper) EnableCORS(cfg CORS) {
+	if cfg.AllowCredentials {
+		for _, o := range cfg.AllowedOrigins {
+			if o == "*" {
+				panic("CORS: wildcard origin can not be allowed with credentials, use AllowOriginFunc")
+			}
+		}
+	}
+
+	c := &corsRoutes{
+		cfg:     cfg,
+		wrapper: r,
+		routes:  r.Router,
+		headers: make(map[string]corsHeaders),
+	}
+
+	r.Wrap(c.collect)
+	r.Router.Use(c.middleware)
+}
+
+// collect captures request and response headers of use case handlers.
+func (c *corsRoutes) collect(h http.Handler) http.Handler {
+	if nethttp.IsWrapperChecker(h) {
+		return h
+	}
+
+	var (
+		withRoute rest.HandlerWithRoute
+		handler   *nethttp.Handler
+	)
+
+	if !nethttp.HandlerAs(h, &withRoute) || !nethttp.HandlerAs(h, &handler) {
+		return h
+	}
+
+	var ch corsHeaders
+
+	method := withRoute.RouteMethod()
+	if method == "" || method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch {
+		ch.allowed = append(ch.allowed, "Content-Type")
+	}
+
+	if m, ok := handler.ReqMapping[rest.ParamInHeader]; ok {
+		for _, name := range m {
+			ch.allowed = append(ch.allowed, name)
+		}
+	} else if withInput, ok := handler.UseCase().(usecase.HasInputPort); ok {
+		ch.allowed = append(ch.allowed, taggedHeaders(withInput.InputPort())...)
+	}
+
+	if len(handler.RespHeaderMapping) > 0 {
+		for _, name := range handler.RespHeaderMapping {
+			ch.exposed = append(ch.exposed, name)
+		}
+	} else if withOutput, ok := handler.UseCase().(usecase.HasOutputPort); ok {
+		output := withOutput.OutputPort()
+		ch.exposed = append(ch.exposed, taggedHeaders(output)...)
+
+		if _, ok := output.(rest.ETagged); ok {
+			ch.exposed = append(ch.exposed, "Etag")
+		}
+
+		if _, ok := output.(rest.WithWebLinks); ok {
+			ch.exposed = append(ch.exposed, "Link")
+		}
+	}
+
+	ch.allowed = uniqueHeaders(ch.allowed)
+	ch.exposed = uniqueHeaders(ch.exposed)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.headers[method+" "+withRoute.RoutePattern()] = ch
+
+	return h
+}
+
+func taggedHeaders(v interface{}) []string {
+	if v == nil {
+		return nil
+	}
+
+	var names []string
+
+	refl.WalkTaggedFields(reflect.ValueOf(v), func(_ reflect.Value, _ reflect.StructField, tag string) {
+		names = append(names, tag)
+	}, string(rest.ParamInHeader))
+
+	return names
+}
+
+func uniqueHeaders(lists ...[]string) []string {
+	var (
+		seen = make(map[string]bool)
+		res  []string
+	)
+
+	for _, names := range lists {
+		for _, n := range names {
+			n = http.CanonicalHeaderKey(n)
+			if seen[n] {
+				continue
+			}
+
+			seen[n] = true
+
+			res = append(res, n)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func (c *corsRoutes) routeHeaders(method, path string) corsHeaders {
+	pattern := c.routes.Find(chi.NewRouteContext(), method, path)
+	if pattern == "" {
+		return corsHeaders{}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if ch, ok := c.headers[method+" "+pattern]; ok {
+		return ch
+	}
+
+	// Handler for all methods.
+	return c.headers[" "+pattern]
+}
+
+func (c *corsRoutes) allowedOrigin(r *http.Request, origin string) (string, bool) {
+	for _, o := range c.cfg.AllowedOrigins {
+		if o == origin {
+			return origin, true
+		}
+
+		if o == "*" {
+			return "*", true
+		}
+	}
+
+	if c.cfg.AllowOriginFunc != nil && c.cfg.AllowOriginFunc(r, origin) {
+		return origin, true
+	}
+
+	return "", false
+}
+
+func (c *corsRoutes) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(rw, r)
+
+			return
+		}
+
+		path := r.URL.RawPath
+		if path == "" {
+			path = r.URL.Path
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" &&
+			c.preflight(rw, r, origin, path) {
+			return
+		}
+
+		h := rw.Header()
+		h.Add("Vary", "Origin")
+
+		allowOrigin, ok := c.allowedOrigin(r, origin)
+		if !ok {
+			next.ServeHTTP(rw, r)
+
+			return
+		}
+
+		h.Set("Access-Control-Allow-Origin", allowOrigin)
+
+		if c.cfg.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		ch := c.routeHeaders(r.Method, path)
+
+		if exposed := uniqueHeaders(ch.exposed, c.cfg.ExposedHeaders); len(exposed) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(exposed, ", "))
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// preflight responds to preflight request, it returns false if path is not routed.
+func (c *corsRoutes) preflight(rw http.ResponseWriter, r *http.Request, origin, path string) bool {
+	methods := c.wrapper.allowedMethods(c.routes, path)
+	if len(methods) == 0 {
+		return false
+	}
+
+	h := rw.Header()
+	h.Add("Vary", "Origin")
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+
+	allowOrigin, ok := c.allowedOrigin(r, origin)
+	if !ok {
+		rw.WriteHeader(http.StatusNoContent)
+
+		return true
+	}
+
+	h.Set("Access-Control-Allow-Origin", allowOrigin)
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+	if c.cfg.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+
+	ch := c.routeHeaders(r.Header.Get("Access-Control-Request-Method"), path)
+
+	if allowed := uniqueHeaders(ch.allowed, c.cfg.AllowedHeaders); len(allowed) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(allowed, ", "))
+	}
+
+	if c.cfg.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(c.cfg.MaxAge/time.Second)))
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+
+	return true
+}
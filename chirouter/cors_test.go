@@ -0,0 +1,141 @@
+package chirouter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/chirouter"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+)
+
+func TestWrapper_EnableCORS(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector(), func(s *web.Service) {
+		s.CORS = &chirouter.CORS{
+			AllowedOrigins:   []string{"https://example.com"},
+			AllowCredentials: true,
+			AllowedHeaders:   []string{"Authorization"},
+			MaxAge:           time.Hour,
+		}
+	})
+
+	type getInput struct {
+		ID      int    `path:"id"`
+		Version string `header:"X-Version"`
+	}
+
+	type getOutput struct {
+		Name      string `json:"name"`
+		RequestID string `header:"X-Request-Id"`
+	}
+
+	type putInput struct {
+		ID      int    `path:"id"`
+		IfMatch string `header:"If-Match"`
+		Name    string `json:"name"`
+	}
+
+	type optionsInput struct {
+		ID int `path:"id"`
+	}
+
+	s.Get("/items/{id}", usecase.NewInteractor(func(_ context.Context, _ getInput, out *getOutput) error {
+		out.Name = "foo"
+		out.RequestID = "abc"
+
+		return nil
+	}))
+
+	s.Put("/items/{id}", usecase.NewInteractor(func(_ context.Context, _ putInput, _ *struct{}) error {
+		return nil
+	}))
+
+	s.Options("/items/{id}", usecase.NewInteractor(func(_ context.Context, _ optionsInput, _ *struct{}) error {
+		return nil
+	}))
+
+	// Preflight.
+	req := httptest.NewRequest(http.MethodOptions, "/items/1", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	assert.Equal(t, "https://example.com", rw.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, PUT, OPTIONS", rw.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization, Content-Type, If-Match", rw.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "true", rw.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "3600", rw.Header().Get("Access-Control-Max-Age"))
+
+	// Actual request.
+	req = httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set("Origin", "https://example.com")
+
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "https://example.com", rw.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-Id", rw.Header().Get("Access-Control-Expose-Headers"))
+	assert.Equal(t, "Origin", rw.Header().Get("Vary"))
+
+	// Non-preflight OPTIONS request is served by use case.
+	req = httptest.NewRequest(http.MethodOptions, "/items/1", nil)
+	req.Header.Set("Origin", "https://example.com")
+
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Methods"))
+
+	// Unknown origin.
+	req = httptest.NewRequest(http.MethodOptions, "/items/1", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Origin"))
+
+	// Unknown path.
+	req = httptest.NewRequest(http.MethodOptions, "/unknown", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestWrapper_EnableCORS_wildcardCredentials(t *testing.T) {
+	r := chirouter.NewWrapper(chi.NewRouter())
+
+	assert.PanicsWithValue(t, "CORS: wildcard origin can not be allowed with credentials, use AllowOriginFunc", func() {
+		r.EnableCORS(chirouter.CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	})
+
+	r = chirouter.NewWrapper(chi.NewRouter())
+	r.EnableCORS(chirouter.CORS{AllowedOrigins: []string{"*"}})
+	r.Get("/", func(_ http.ResponseWriter, _ *http.Request) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, req)
+
+	assert.Equal(t, "*", rw.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -23,6 +23,8 @@ type Wrapper struct {
 	chi.Router
 	name        string
 	basePattern string
+	autoOptions bool
+	asyncMethod func(r chi.Router, method, pattern string, h http.Handler)
 
 	middlewares []func(http.Handler) http.Handler
 	wraps       []func(http.Handler) http.Handler
@@ -36,6 +38,8 @@ func (r *Wrapper) copy(router chi.Router, pattern string) *Wrapper {
 		Router:      router,
 		name:        r.name,
 		basePattern: r.basePattern + pattern,
+		autoOptions: r.autoOptions,
+		asyncMethod: r.asyncMethod,
 		middlewares: r.middlewares,
 		wraps:       r.wraps,
 	}
@@ -143,7 +147,19 @@ func (r *Wrapper) Handle(pattern string, h http.Handler) {
 }
 
 // Method adds routes for `basePattern` that matches the `method` HTTP method.
+//
+// Handlers of async use cases are added with function configured by EnableAsync.
 func (r *Wrapper) Method(method, pattern string, h http.Handler) {
+	if isAsync(h) {
+		if r.asyncMethod == nil {
+			panic("async use case handler requires EnableAsync: " + method + " " + r.resolvePattern(pattern))
+		}
+
+		r.asyncMethod(r, method, pattern, h)
+
+		return
+	}
+
 	h = r.prepareHandler(method, pattern, h)
 	r.captureHandler(h)
 	r.Router.Method(method, pattern, h)
@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/swaggest/usecase"
+)
+
+// Deprecation describes lifecycle of a deprecated use case.
+//
+// It is a use case middleware, wrapping use case with it marks use case as deprecated:
+//
+//	u = usecase.Wrap(u, rest.Deprecation{Sunset: sunset, Link: "/v2/items"})
+//
+// Handler of deprecated use case emits Deprecation, Sunset and Link response headers.
+type Deprecation struct {
+	// Since is the moment of deprecation, zero value means deprecated without a known date.
+	Since time.Time
+
+	// Sunset is the moment after which use case is expected to become unavailable, optional.
+	Sunset time.Time
+
+	// Link is an optional URL of replacement, it is exposed as "successor-version" web link.
+	Link string
+}
+
+// WithDeprecation is implemented by deprecated use case to expose deprecation lifecycle.
+type WithDeprecation interface {
+	Deprecation() Deprecation
+}
+
+// Wrap implements usecase.Middleware.
+func (d Deprecation) Wrap(u usecase.Interactor) usecase.Interactor {
+	return deprecatedInteractor{Interactor: u, deprecation: d}
+}
+
+// IsSunset reports whether sunset date is passed at the moment.
+func (d Deprecation) IsSunset(now time.Time) bool {
+	return !d.Sunset.IsZero() && !now.Before(d.Sunset)
+}
+
+// SetHeaders adds Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers.
+func (d Deprecation) SetHeaders(h http.Header) {
+	if d.Since.IsZero() {
+		h.Set("Deprecation", "true")
+	} else {
+		h.Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	}
+
+	if !d.Sunset.IsZero() {
+		h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+
+	if d.Link != "" {
+		h.Add("Link", WebLink{URL: d.Link, Rel: "successor-version"}.String())
+	}
+}
+
+// UseCaseDeprecation returns deprecation lifecycle of use case and true if use case implements WithDeprecation.
+//
+// Use cases that are only marked with usecase.HasIsDeprecated are documented as deprecated, but do not emit headers.
+func UseCaseDeprecation(u usecase.Interactor) (Deprecation, bool) {
+	var withDeprecation WithDeprecation
+
+	if usecase.As(u, &withDeprecation) {
+		return withDeprecation.Deprecation(), true
+	}
+
+	return Deprecation{}, false
+}
+
+type deprecatedInteractor struct {
+	usecase.Interactor
+	deprecation Deprecation
+}
+
+// IsDeprecated implements usecase.HasIsDeprecated.
+func (d deprecatedInteractor) IsDeprecated() bool {
+	return true
+}
+
+// Deprecation implements WithDeprecation.
+func (d deprecatedInteractor) Deprecation() Deprecation {
+	return d.deprecation
+}
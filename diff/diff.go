@@ -0,0 +1,529 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// Kind identifies type of change.
+type Kind string
+
+// Change kinds.
+const (
+	OperationRemoved      = Kind("operation-removed")
+	OperationAdded        = Kind("operation-added")
+	ParameterRemoved      = Kind("parameter-removed")
+	ParameterAdded        = Kind("parameter-added")
+	ParameterRequired     = Kind("parameter-became-required")
+	ParameterOptional     = Kind("parameter-became-optional")
+	RequestBodyRemoved    = Kind("request-body-removed")
+	RequestBodyAdded      = Kind("request-body-added")
+	RequestBodyRequired   = Kind("request-body-became-required")
+	RequestBodyOptional   = Kind("request-body-became-optional")
+	ResponseRemoved       = Kind("response-removed")
+	ResponseAdded         = Kind("response-added")
+	ResponseHeaderRemoved = Kind("response-header-removed")
+	ResponseHeaderAdded   = Kind("response-header-added")
+	SecurityRemoved       = Kind("security-requirement-removed")
+	SecurityAdded         = Kind("security-requirement-added")
+	ContentTypeRemoved    = Kind("content-type-removed")
+	ContentTypeAdded      = Kind("content-type-added")
+	TypeChanged           = Kind("type-changed")
+	EnumValueRemoved      = Kind("enum-value-removed")
+	EnumValueAdded        = Kind("enum-value-added")
+	PropertyRemoved       = Kind("property-removed")
+	PropertyAdded         = Kind("property-added")
+	PropertyRequired      = Kind("property-became-required")
+	PropertyOptional      = Kind("property-became-optional")
+	ConstraintChanged     = Kind("constraint-changed")
+	CompositionChanged    = Kind("composition-changed")
+)
+
+// Change describes a difference between two documents.
+type Change struct {
+	Kind     Kind `json:"kind"`
+	Breaking bool `json:"breaking"`
+
+	// Operation is an HTTP method and path, for example "GET /users/{id}".
+	Operation string `json:"operation"`
+
+	// Location is a part of operation, for example "parameters/query/limit",
+	// "requestBody/application/json" or "responses/200/application/json".
+	Location string `json:"location,omitempty"`
+
+	// Pointer is a JSON pointer within schema of location, for example "/properties/name".
+	Pointer string `json:"pointer,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// String returns human-readable change description.
+func (c Change) String() string {
+	s := c.Operation
+
+	if c.Location != "" {
+		s += " " + c.Location
+	}
+
+	if c.Pointer != "" {
+		s += " #" + c.Pointer
+	}
+
+	return s + ": " + c.Message
+}
+
+// Report contains changes between two documents.
+type Report struct {
+	Breaking    int      `json:"breaking"`
+	NonBreaking int      `json:"nonBreaking"`
+	Changes     []Change `json:"changes"`
+}
+
+// HasBreaking indicates if report has at least one breaking change.
+func (r *Report) HasBreaking() bool {
+	return r.Breaking > 0
+}
+
+// BreakingChanges returns breaking changes.
+func (r *Report) BreakingChanges() []Change {
+	var res []Change
+
+	for _, c := range r.Changes {
+		if c.Breaking {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+// String returns human-readable report, one change per line.
+func (r *Report) String() string {
+	b := strings.Builder{}
+
+	for _, c := range r.Changes {
+		if c.Breaking {
+			b.WriteString("[breaking] ")
+		} else {
+			b.WriteString("[non-breaking] ")
+		}
+
+		b.WriteString(c.String())
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func (r *Report) add(c Change) {
+	if c.Breaking {
+		r.Breaking++
+	} else {
+		r.NonBreaking++
+	}
+
+	r.Changes = append(r.Changes, c)
+}
+
+// CompareSpecs compares OpenAPI 3.0 or 3.1 documents, for example collected by openapi.Collector.
+func CompareSpecs(base, revision openapi.SpecSchema) (*Report, error) {
+	b, err := json.Marshal(base)
+	if err != nil {
+		return nil, fmt.Errorf("marshal base: %w", err)
+	}
+
+	r, err := json.Marshal(revision)
+	if err != nil {
+		return nil, fmt.Errorf("marshal revision: %w", err)
+	}
+
+	return Compare(b, r)
+}
+
+// Compare compares JSON OpenAPI 3.0 or 3.1 documents.
+func Compare(base, revision []byte) (*Report, error) {
+	c := comparer{report: &Report{Changes: []Change{}}}
+
+	if err := json.Unmarshal(base, &c.base); err != nil {
+		return nil, fmt.Errorf("unmarshal base: %w", err)
+	}
+
+	if err := json.Unmarshal(revision, &c.rev); err != nil {
+		return nil, fmt.Errorf("unmarshal revision: %w", err)
+	}
+
+	c.operations()
+
+	return c.report, nil
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+type comparer struct {
+	base, rev obj
+	report    *Report
+
+	operation string
+	location  string
+	visited   map[string]bool
+}
+
+func (c *comparer) add(kind Kind, breaking bool, pointer, format string, args ...interface{}) {
+	c.report.add(Change{
+		Kind:      kind,
+		Breaking:  breaking,
+		Operation: c.operation,
+		Location:  c.location,
+		Pointer:   pointer,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparer) operations() {
+	bp := c.base.obj("paths")
+	rp := c.rev.obj("paths")
+
+	for _, path := range keys(bp, rp) {
+		bItem := c.base.resolve(bp.obj(path))
+		rItem := c.rev.resolve(rp.obj(path))
+
+		for _, method := range methods {
+			bOp := bItem.obj(method)
+			rOp := rItem.obj(method)
+
+			if bOp == nil && rOp == nil {
+				continue
+			}
+
+			c.operation = strings.ToUpper(method) + " " + path
+			c.location = ""
+
+			switch {
+			case rOp == nil:
+				c.add(OperationRemoved, true, "", "operation removed")
+			case bOp == nil:
+				c.add(OperationAdded, false, "", "operation added")
+			default:
+				c.parameters(bItem, rItem, bOp, rOp)
+				c.security(bOp, rOp)
+				c.requestBody(c.base.resolve(bOp.obj("requestBody")), c.rev.resolve(rOp.obj("requestBody")))
+				c.responses(bOp.obj("responses"), rOp.obj("responses"))
+			}
+		}
+	}
+}
+
+func (c *comparer) parameters(bItem, rItem, bOp, rOp obj) {
+	bParams := c.base.params(bItem, bOp)
+	rParams := c.rev.params(rItem, rOp)
+
+	for _, k := range keys(bParams, rParams) {
+		bParam := bParams.obj(k)
+		rParam := rParams.obj(k)
+
+		c.location = "parameters/" + k
+		c.visited = map[string]bool{}
+
+		switch {
+		case rParam == nil:
+			c.add(ParameterRemoved, false, "", "parameter removed")
+		case bParam == nil:
+			required := rParam.bool("required")
+			c.add(ParameterAdded, required, "", "%s parameter added", requiredText(required))
+		default:
+			if !bParam.bool("required") && rParam.bool("required") {
+				c.add(ParameterRequired, true, "", "parameter became required")
+			} else if bParam.bool("required") && !rParam.bool("required") {
+				c.add(ParameterOptional, false, "", "parameter became optional")
+			}
+
+			c.schema("", paramSchema(bParam), paramSchema(rParam), true)
+		}
+	}
+}
+
+func (c *comparer) requestBody(bBody, rBody obj) {
+	c.location = "requestBody"
+
+	switch {
+	case bBody == nil && rBody == nil:
+		return
+	case rBody == nil:
+		c.add(RequestBodyRemoved, false, "", "request body removed")
+
+		return
+	case bBody == nil:
+		required := rBody.bool("required")
+		c.add(RequestBodyAdded, required, "", "%s request body added", requiredText(required))
+
+		return
+	}
+
+	if !bBody.bool("required") && rBody.bool("required") {
+		c.add(RequestBodyRequired, true, "", "request body became required")
+	} else if bBody.bool("required") && !rBody.bool("required") {
+		c.add(RequestBodyOptional, false, "", "request body became optional")
+	}
+
+	c.content("requestBody", bBody.obj("content"), rBody.obj("content"), true)
+}
+
+func (c *comparer) responses(bResp, rResp obj) {
+	for _, status := range keys(bResp, rResp) {
+		b := c.base.resolve(bResp.obj(status))
+		r := c.rev.resolve(rResp.obj(status))
+
+		c.location = "responses/" + status
+
+		switch {
+		case r == nil:
+			success := strings.HasPrefix(status, "2")
+			c.add(ResponseRemoved, success, "", "response removed")
+		case b == nil:
+			c.add(ResponseAdded, false, "", "response added")
+		default:
+			c.headers("responses/"+status, b.obj("headers"), r.obj("headers"))
+			c.content("responses/"+status, b.obj("content"), r.obj("content"), false)
+		}
+	}
+}
+
+func (c *comparer) headers(location string, bHeaders, rHeaders obj) {
+	for _, name := range keys(bHeaders, rHeaders) {
+		b := c.base.resolve(bHeaders.obj(name))
+		r := c.rev.resolve(rHeaders.obj(name))
+
+		c.location = location + "/headers/" + name
+		c.visited = map[string]bool{}
+
+		switch {
+		case r == nil:
+			c.add(ResponseHeaderRemoved, true, "", "response header removed")
+		case b == nil:
+			c.add(ResponseHeaderAdded, false, "", "response header added")
+		default:
+			c.schema("", paramSchema(b), paramSchema(r), false)
+		}
+	}
+}
+
+// security compares alternative security requirements of operations.
+//
+// Removed alternative is breaking for clients that use it, added alternative is breaking
+// if operation had no security or allowed anonymous access before.
+func (c *comparer) security(bOp, rOp obj) {
+	b := c.base.requirements(bOp)
+	r := c.rev.requirements(rOp)
+
+	c.location = "security"
+
+	open := len(b) == 0
+	for _, req := range b {
+		if req == "" {
+			open = true
+		}
+	}
+
+	for _, req := range subtract(b, r) {
+		c.add(SecurityRemoved, true, "", "security requirement removed: %s", requirementText(req))
+	}
+
+	for _, req := range subtract(r, b) {
+		c.add(SecurityAdded, open, "", "security requirement added: %s", requirementText(req))
+	}
+}
+
+func requirementText(req string) string {
+	if req == "" {
+		return "anonymous"
+	}
+
+	return req
+}
+
+func (c *comparer) content(location string, bContent, rContent obj, request bool) {
+	for _, ct := range keys(bContent, rContent) {
+		b := bContent.obj(ct)
+		r := rContent.obj(ct)
+
+		c.location = location + "/" + ct
+		c.visited = map[string]bool{}
+
+		switch {
+		case r == nil:
+			c.add(ContentTypeRemoved, true, "", "content type removed")
+		case b == nil:
+			c.add(ContentTypeAdded, false, "", "content type added")
+		default:
+			c.schema("", b.obj("schema"), r.obj("schema"), request)
+		}
+	}
+}
+
+func requiredText(required bool) string {
+	if required {
+		return "required"
+	}
+
+	return "optional"
+}
+
+func paramSchema(p obj) obj {
+	if s := p.obj("schema"); s != nil {
+		return s
+	}
+
+	content := p.obj("content")
+	for _, ct := range keys(content, nil) {
+		return content.obj(ct).obj("schema")
+	}
+
+	return nil
+}
+
+// obj is a JSON object.
+type obj map[string]interface{}
+
+func (o obj) obj(key string) obj {
+	if o == nil {
+		return nil
+	}
+
+	m, ok := o[key].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	return m
+}
+
+func (o obj) bool(key string) bool {
+	if o == nil {
+		return false
+	}
+
+	b, ok := o[key].(bool)
+
+	return b && ok
+}
+
+func (o obj) ref() string {
+	if o == nil {
+		return ""
+	}
+
+	r, _ := o["$ref"].(string) //nolint:errcheck // Zero value is handled.
+
+	return r
+}
+
+// resolve follows local references of the document.
+func (o obj) resolve(v obj) obj {
+	for i := 0; i < 32 && v.ref() != ""; i++ {
+		ref := v.ref()
+		if !strings.HasPrefix(ref, "#/") {
+			return v
+		}
+
+		cur := o
+
+		for _, tok := range strings.Split(ref[2:], "/") {
+			tok = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+			cur = cur.obj(tok)
+		}
+
+		if cur == nil {
+			return v
+		}
+
+		v = cur
+	}
+
+	return v
+}
+
+// requirements returns operation or global security requirements as sorted
+// "<scheme> (<scopes>) + <scheme>" strings, empty requirement allows anonymous access.
+func (o obj) requirements(op obj) []string {
+	sec, ok := op["security"]
+	if !ok {
+		sec = o["security"]
+	}
+
+	list, _ := sec.([]interface{}) //nolint:errcheck // Zero value is handled.
+	res := make([]string, 0, len(list))
+
+	for _, item := range list {
+		req, _ := item.(map[string]interface{}) //nolint:errcheck // Zero value is handled.
+		schemes := make([]string, 0, len(req))
+
+		for _, name := range keys(req, nil) {
+			values := make([]string, 0)
+			for scope := range stringSet(req[name]) {
+				values = append(values, scope)
+			}
+
+			sort.Strings(values)
+
+			if len(values) > 0 {
+				name += " (" + strings.Join(values, ", ") + ")"
+			}
+
+			schemes = append(schemes, name)
+		}
+
+		res = append(res, strings.Join(schemes, " + "))
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// params returns resolved path item and operation parameters keyed by "<in>/<name>".
+func (o obj) params(item, op obj) obj {
+	res := obj{}
+
+	for _, ps := range []interface{}{item["parameters"], op["parameters"]} {
+		list, _ := ps.([]interface{}) //nolint:errcheck // Zero value is handled.
+
+		for _, p := range list {
+			m, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			param := o.resolve(m)
+
+			in, _ := param["in"].(string)     //nolint:errcheck // Zero value is handled.
+			name, _ := param["name"].(string) //nolint:errcheck // Zero value is handled.
+
+			res[in+"/"+name] = map[string]interface{}(param)
+		}
+	}
+
+	return res
+}
+
+// keys returns sorted union of object keys.
+func keys(a, b obj) []string {
+	res := make([]string, 0, len(a)+len(b))
+
+	for k := range a {
+		res = append(res, k)
+	}
+
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			res = append(res, k)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
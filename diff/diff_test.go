@@ -0,0 +1,259 @@
+package diff_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	oapi "github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/diff"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/usecase"
+)
+
+func baseSpec(t *testing.T, r oapi.Reflector) oapi.SpecSchema {
+	t.Helper()
+
+	type getUser struct {
+		ID     int    `path:"id"`
+		Fields string `query:"fields"`
+	}
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Role string `json:"role" enum:"admin,user"`
+	}
+
+	type createUser struct {
+		Name string `json:"name" required:"true"`
+		Role string `json:"role" enum:"admin,user"`
+	}
+
+	c := openapi.NewCollector(r)
+
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/users/{id}",
+		usecase.NewInteractor(func(_ context.Context, _ getUser, _ *user) error { return nil }), rest.HandlerTrait{}))
+	require.NoError(t, c.CollectUseCase(http.MethodDelete, "/users/{id}",
+		usecase.NewInteractor(func(_ context.Context, _ getUser, _ *struct{}) error { return nil }), rest.HandlerTrait{}))
+	require.NoError(t, c.CollectUseCase(http.MethodPost, "/users",
+		usecase.NewInteractor(func(_ context.Context, _ createUser, _ *user) error { return nil }), rest.HandlerTrait{}))
+
+	return c.SpecSchema()
+}
+
+func revisionSpec(t *testing.T, r oapi.Reflector) oapi.SpecSchema {
+	t.Helper()
+
+	type getUser struct {
+		ID     int    `path:"id"`
+		Fields string `query:"fields" required:"true"`
+		Expand bool   `query:"expand"`
+	}
+
+	type user struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+		Role  string `json:"role" enum:"admin,user,owner"`
+	}
+
+	type createUser struct {
+		Name  string `json:"name" required:"true" maxLength:"64"`
+		Email string `json:"email" required:"true"`
+		Age   int    `json:"age"`
+		Role  string `json:"role" enum:"user"`
+	}
+
+	c := openapi.NewCollector(r)
+
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/users/{id}",
+		usecase.NewInteractor(func(_ context.Context, _ getUser, _ *user) error { return nil }), rest.HandlerTrait{}))
+	require.NoError(t, c.CollectUseCase(http.MethodPost, "/users",
+		usecase.NewInteractor(func(_ context.Context, _ createUser, _ *user) error { return nil }), rest.HandlerTrait{}))
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/health",
+		usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error { return nil }), rest.HandlerTrait{}))
+
+	return c.SpecSchema()
+}
+
+func TestCompareSpecs(t *testing.T) {
+	for _, newReflector := range []func() oapi.Reflector{
+		func() oapi.Reflector { return openapi3.NewReflector() },
+		func() oapi.Reflector { return openapi31.NewReflector() },
+	} {
+		report, err := diff.CompareSpecs(baseSpec(t, newReflector()), revisionSpec(t, newReflector()))
+		require.NoError(t, err)
+
+		assert.True(t, report.HasBreaking())
+		assert.Len(t, report.BreakingChanges(), report.Breaking)
+
+		assertjson.EqMarshal(t, `{
+		  "breaking":11,"nonBreaking":5,
+		  "changes":[
+			{"kind":"operation-added","breaking":false,"operation":"GET /health","message":"operation added"},
+			{
+			  "kind":"property-added","breaking":false,"operation":"POST /users","location":"requestBody/application/json",
+			  "pointer":"/properties/age","message":"optional property added"
+			},
+			{
+			  "kind":"property-added","breaking":true,"operation":"POST /users","location":"requestBody/application/json",
+			  "pointer":"/properties/email","message":"required property added"
+			},
+			{
+			  "kind":"constraint-changed","breaking":true,"operation":"POST /users","location":"requestBody/application/json",
+			  "pointer":"/properties/name/maxLength","message":"maxLength changed from none to 64"
+			},
+			{
+			  "kind":"enum-value-removed","breaking":true,"operation":"POST /users","location":"requestBody/application/json",
+			  "pointer":"/properties/role/enum","message":"enum values removed: \"admin\""
+			},
+			{
+			  "kind":"property-added","breaking":false,"operation":"POST /users","location":"responses/200/application/json",
+			  "pointer":"/properties/email","message":"optional property added"
+			},
+			{
+			  "kind":"type-changed","breaking":true,"operation":"POST /users","location":"responses/200/application/json",
+			  "pointer":"/properties/id","message":"type changed from integer to string"
+			},
+			{
+			  "kind":"property-removed","breaking":true,"operation":"POST /users","location":"responses/200/application/json",
+			  "pointer":"/properties/name","message":"property removed"
+			},
+			{
+			  "kind":"enum-value-added","breaking":true,"operation":"POST /users","location":"responses/200/application/json",
+			  "pointer":"/properties/role/enum","message":"enum values added: \"owner\""
+			},
+			{
+			  "kind":"parameter-added","breaking":false,"operation":"GET /users/{id}",
+			  "location":"parameters/query/expand","message":"optional parameter added"
+			},
+			{
+			  "kind":"parameter-became-required","breaking":true,"operation":"GET /users/{id}",
+			  "location":"parameters/query/fields","message":"parameter became required"
+			},
+			{
+			  "kind":"property-added","breaking":false,"operation":"GET /users/{id}","location":"responses/200/application/json",
+			  "pointer":"/properties/email","message":"optional property added"
+			},
+			{
+			  "kind":"type-changed","breaking":true,"operation":"GET /users/{id}","location":"responses/200/application/json",
+			  "pointer":"/properties/id","message":"type changed from integer to string"
+			},
+			{
+			  "kind":"property-removed","breaking":true,"operation":"GET /users/{id}","location":"responses/200/application/json",
+			  "pointer":"/properties/name","message":"property removed"
+			},
+			{
+			  "kind":"enum-value-added","breaking":true,"operation":"GET /users/{id}","location":"responses/200/application/json",
+			  "pointer":"/properties/role/enum","message":"enum values added: \"owner\""
+			},
+			{"kind":"operation-removed","breaking":true,"operation":"DELETE /users/{id}","message":"operation removed"}
+		  ]
+		}`, report)
+	}
+}
+
+func TestCompare_securityHeadersComposition(t *testing.T) {
+	base := []byte(`{
+	  "openapi":"3.0.3","security":[{"apiKey":[]}],
+	  "paths":{
+		"/items":{
+		  "get":{
+			"responses":{"200":{
+			  "description":"OK",
+			  "headers":{"X-Total":{"schema":{"type":"integer"}},"X-Page":{"schema":{"type":"integer"}}},
+			  "content":{"application/json":{"schema":{"oneOf":[{"type":"string"},{"type":"integer"}]}}}
+			}}
+		  },
+		  "post":{
+			"security":[],
+			"requestBody":{"content":{"application/json":{"schema":{"anyOf":[{"type":"string"},{"type":"integer"}]}}}},
+			"responses":{"204":{"description":"No Content"}}
+		  },
+		  "put":{
+			"security":[{"oauth":["write"]}],
+			"requestBody":{"content":{"application/json":{"schema":{"allOf":[{"type":"object"}]}}}},
+			"responses":{"204":{"description":"No Content"}}
+		  }
+		}
+	  }
+	}`)
+
+	revision := []byte(`{
+	  "openapi":"3.0.3","security":[{"apiKey":[]},{"oauth":["read"]}],
+	  "paths":{
+		"/items":{
+		  "get":{
+			"responses":{"200":{
+			  "description":"OK",
+			  "headers":{"X-Total":{"schema":{"type":"string"}},"X-Next":{"schema":{"type":"string"}}},
+			  "content":{"application/json":{"schema":{"oneOf":[{"type":"string"},{"type":"integer"},{"type":"null"}]}}}
+			}}
+		  },
+		  "post":{
+			"security":[{"apiKey":[]}],
+			"requestBody":{"content":{"application/json":{"schema":{"anyOf":[{"type":"string"}]}}}},
+			"responses":{"204":{"description":"No Content"}}
+		  },
+		  "put":{
+			"security":[{"oauth":["write","admin"]}],
+			"requestBody":{"content":{"application/json":{"schema":{"allOf":[]}}}},
+			"responses":{"204":{"description":"No Content"}}
+		  }
+		}
+	  }
+	}`)
+
+	report, err := diff.Compare(base, revision)
+	require.NoError(t, err)
+
+	assert.Equal(t, `[non-breaking] GET /items security: security requirement added: oauth (read)
+[non-breaking] GET /items responses/200/headers/X-Next: response header added
+[breaking] GET /items responses/200/headers/X-Page: response header removed
+[breaking] GET /items responses/200/headers/X-Total: type changed from integer to string
+[breaking] GET /items responses/200/application/json #/oneOf: oneOf has 3 schemas instead of 2
+[breaking] PUT /items security: security requirement removed: oauth (write)
+[non-breaking] PUT /items security: security requirement added: oauth (admin, write)
+[non-breaking] PUT /items requestBody/application/json #/allOf: allOf has 0 schemas instead of 1
+[breaking] POST /items security: security requirement added: apiKey
+[breaking] POST /items requestBody/application/json #/anyOf: anyOf has 1 schemas instead of 2
+`, report.String())
+}
+
+func TestAssertCompatible(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "openapi.json")
+
+	j, err := json.Marshal(baseSpec(t, openapi3.NewReflector()))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(golden, j, 0o600))
+
+	assert.True(t, diff.AssertCompatible(t, golden, baseSpec(t, openapi3.NewReflector())))
+
+	mt := &mockT{}
+	assert.False(t, diff.AssertCompatible(mt, golden, revisionSpec(t, openapi3.NewReflector())))
+	assert.Contains(t, mt.err, "[breaking] DELETE /users/{id}: operation removed")
+
+	mt = &mockT{}
+	assert.False(t, diff.AssertCompatible(mt, golden+".missing", baseSpec(t, openapi3.NewReflector())))
+	assert.Contains(t, mt.err, "read golden spec")
+}
+
+type mockT struct {
+	err string
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Errorf(format string, args ...interface{}) {
+	m.err += fmt.Sprintf(format, args...)
+}
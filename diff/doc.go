@@ -0,0 +1,2 @@
+// Package diff detects breaking changes between two OpenAPI documents.
+package diff
@@ -0,0 +1,50 @@
+package diff
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// TestingT is a subset of testing.TB.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertCompatible checks that spec has no breaking changes compared to golden JSON document.
+//
+// Golden document is usually committed to repository and updated when changes are released.
+func AssertCompatible(t TestingT, goldenFile string, spec openapi.SpecSchema) bool {
+	t.Helper()
+
+	golden, err := os.ReadFile(goldenFile) //nolint:gosec // Path is provided by test.
+	if err != nil {
+		t.Errorf("read golden spec: %v", err)
+
+		return false
+	}
+
+	rev, err := json.Marshal(spec)
+	if err != nil {
+		t.Errorf("marshal spec: %v", err)
+
+		return false
+	}
+
+	report, err := Compare(golden, rev)
+	if err != nil {
+		t.Errorf("compare with golden spec %s: %v", goldenFile, err)
+
+		return false
+	}
+
+	if report.HasBreaking() {
+		t.Errorf("breaking changes compared to %s:\n%s", goldenFile, report.String())
+
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,302 @@
+package diff
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schema compares schemas, request direction indicates that schemas describe data sent by clients.
+//
+// Narrowing of accepted values is breaking for requests, widening of produced values is breaking for responses.
+func (c *comparer) schema(pointer string, b, r obj, request bool) {
+	if b == nil || r == nil {
+		return
+	}
+
+	if b.ref() != "" && r.ref() != "" {
+		key := b.ref() + " " + r.ref()
+		if c.visited[key] {
+			return
+		}
+
+		c.visited[key] = true
+	}
+
+	b = c.base.resolve(b)
+	r = c.rev.resolve(r)
+
+	c.types(pointer, b, r, request)
+	c.enum(pointer, b, r, request)
+	c.constraints(pointer, b, r, request)
+	c.properties(pointer, b, r, request)
+
+	for _, k := range []string{"items", "additionalProperties", "not"} {
+		c.schema(pointer+"/"+k, b.obj(k), r.obj(k), request)
+	}
+
+	for _, k := range []string{"allOf", "anyOf", "oneOf"} {
+		bl, _ := b[k].([]interface{}) //nolint:errcheck // Zero value is handled.
+		rl, _ := r[k].([]interface{}) //nolint:errcheck // Zero value is handled.
+
+		if len(bl) != len(rl) {
+			// More allOf schemas narrow values, more anyOf or oneOf alternatives widen them,
+			// unless alternatives are added to schema without alternatives.
+			widened := len(rl) < len(bl)
+			if k != "allOf" {
+				widened = len(rl) == 0 || (len(bl) > 0 && len(rl) > len(bl))
+			}
+
+			c.add(CompositionChanged, widened != request, pointer+"/"+k,
+				"%s has %d schemas instead of %d", k, len(rl), len(bl))
+
+			continue
+		}
+
+		for i := range bl {
+			bs, _ := bl[i].(map[string]interface{}) //nolint:errcheck // Zero value is handled.
+			rs, _ := rl[i].(map[string]interface{}) //nolint:errcheck // Zero value is handled.
+
+			c.schema(pointer+"/"+k+"/"+strconv.Itoa(i), bs, rs, request)
+		}
+	}
+}
+
+func (c *comparer) types(pointer string, b, r obj, request bool) {
+	bt := typeSet(b)
+	rt := typeSet(r)
+
+	if len(bt) == 0 && len(rt) == 0 {
+		return
+	}
+
+	var added, removed []string
+
+	switch {
+	case len(bt) == 0:
+		// Any type became restricted.
+		removed = []string{"any"}
+	case len(rt) == 0:
+		added = []string{"any"}
+	default:
+		added = subtract(rt, bt)
+		removed = subtract(bt, rt)
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	breaking := len(added) > 0
+	if request {
+		breaking = len(removed) > 0
+	}
+
+	c.add(TypeChanged, breaking, pointer, "type changed from %s to %s", typeText(bt), typeText(rt))
+}
+
+func (c *comparer) enum(pointer string, b, r obj, request bool) {
+	be, bok := b["enum"].([]interface{})
+	re, rok := r["enum"].([]interface{})
+
+	if !bok && !rok {
+		return
+	}
+
+	if !rok {
+		c.add(EnumValueAdded, !request, pointer+"/enum", "enum restriction removed")
+
+		return
+	}
+
+	if !bok {
+		c.add(EnumValueRemoved, request, pointer+"/enum", "enum restriction added")
+
+		return
+	}
+
+	bv := enumValues(be)
+	rv := enumValues(re)
+
+	if removed := subtract(bv, rv); len(removed) > 0 {
+		c.add(EnumValueRemoved, request, pointer+"/enum", "enum values removed: %s", strings.Join(removed, ", "))
+	}
+
+	if added := subtract(rv, bv); len(added) > 0 {
+		c.add(EnumValueAdded, !request, pointer+"/enum", "enum values added: %s", strings.Join(added, ", "))
+	}
+}
+
+var (
+	lowerBounds = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"}
+	upperBounds = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"}
+)
+
+func (c *comparer) constraints(pointer string, b, r obj, request bool) {
+	bound := func(k string, lower bool) {
+		bv, bok := b[k].(float64)
+		rv, rok := r[k].(float64)
+
+		var tightened bool
+
+		switch {
+		case !bok && !rok:
+			return
+		case !bok:
+			tightened = true
+		case !rok:
+			tightened = false
+		case bv == rv:
+			return
+		case lower:
+			tightened = rv > bv
+		default:
+			tightened = rv < bv
+		}
+
+		c.add(ConstraintChanged, tightened == request, pointer+"/"+k,
+			"%s changed from %s to %s", k, valueText(b[k]), valueText(r[k]))
+	}
+
+	for _, k := range lowerBounds {
+		bound(k, true)
+	}
+
+	for _, k := range upperBounds {
+		bound(k, false)
+	}
+
+	for _, k := range []string{"pattern", "format"} {
+		bv, _ := b[k].(string) //nolint:errcheck // Zero value is handled.
+		rv, _ := r[k].(string) //nolint:errcheck // Zero value is handled.
+
+		if bv == rv {
+			continue
+		}
+
+		// Removed restriction widens values, added or changed restriction may narrow them.
+		tightened := rv != ""
+
+		c.add(ConstraintChanged, tightened == request, pointer+"/"+k,
+			"%s changed from %s to %s", k, valueText(b[k]), valueText(r[k]))
+	}
+}
+
+func (c *comparer) properties(pointer string, b, r obj, request bool) {
+	bp := b.obj("properties")
+	rp := r.obj("properties")
+	br := stringSet(b["required"])
+	rr := stringSet(r["required"])
+
+	for _, name := range keys(bp, rp) {
+		p := pointer + "/properties/" + strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+
+		switch {
+		case rp.obj(name) == nil:
+			c.add(PropertyRemoved, !request, p, "property removed")
+		case bp.obj(name) == nil:
+			required := rr[name]
+			c.add(PropertyAdded, request && required, p, "%s property added", requiredText(required))
+		default:
+			if !br[name] && rr[name] {
+				c.add(PropertyRequired, request, p, "property became required")
+			} else if br[name] && !rr[name] {
+				c.add(PropertyOptional, !request, p, "property became optional")
+			}
+
+			c.schema(p, bp.obj(name), rp.obj(name), request)
+		}
+	}
+}
+
+// typeSet returns sorted types of schema, OpenAPI 3.0 nullable is represented as "null" type.
+func typeSet(s obj) []string {
+	var res []string
+
+	switch t := s["type"].(type) {
+	case string:
+		res = append(res, t)
+	case []interface{}:
+		for _, v := range t {
+			if vs, ok := v.(string); ok {
+				res = append(res, vs)
+			}
+		}
+	}
+
+	if len(res) > 0 && s.bool("nullable") {
+		res = append(res, "null")
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func typeText(t []string) string {
+	if len(t) == 0 {
+		return "any"
+	}
+
+	return strings.Join(t, "|")
+}
+
+func enumValues(values []interface{}) []string {
+	res := make([]string, 0, len(values))
+
+	for _, v := range values {
+		res = append(res, valueText(v))
+	}
+
+	return res
+}
+
+func valueText(v interface{}) string {
+	if v == nil {
+		return "none"
+	}
+
+	j, err := json.Marshal(v)
+	if err != nil {
+		return "?"
+	}
+
+	return string(j)
+}
+
+func stringSet(v interface{}) map[string]bool {
+	res := map[string]bool{}
+
+	list, _ := v.([]interface{}) //nolint:errcheck // Zero value is handled.
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			res[s] = true
+		}
+	}
+
+	return res
+}
+
+// subtract returns items of a that are missing in b.
+func subtract(a, b []string) []string {
+	var res []string
+
+	for _, av := range a {
+		found := false
+
+		for _, bv := range b {
+			if av == bv {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			res = append(res, av)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,2 @@
+// Package inventory lists routes with their use cases, middlewares and documentation.
+package inventory
@@ -0,0 +1,121 @@
+package inventory
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/swaggest/rest/openapi"
+)
+
+// NewHandler creates debug handler that renders route inventory.
+//
+// Collector is optional, if set, it is used to describe route security.
+func NewHandler(routes chi.Routes, c *openapi.Collector) *Handler {
+	return &Handler{
+		Routes:    routes,
+		Collector: c,
+	}
+}
+
+// Handler renders route inventory as JSON or HTML.
+//
+// HTML is rendered for `?format=html` or for requests that accept text/html, JSON is rendered otherwise.
+// Inventory is collected on each request, so routes added after mounting are also listed.
+//
+// Please use NewHandler to create instance.
+type Handler struct {
+	Routes    chi.Routes
+	Collector *openapi.Collector
+}
+
+// ServeHTTP serves route inventory.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	routes, err := Collect(h.Routes, h.Collector)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rw.Header().Set("Vary", "Accept")
+
+	format := r.URL.Query().Get("format")
+	if format == "" && strings.Contains(r.Header.Get("Accept"), "text/html") {
+		format = "html"
+	}
+
+	if format == "html" {
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		if err := page.Execute(rw, routes); err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(rw)
+	enc.SetIndent("", " ")
+
+	if err := enc.Encode(routes); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// requirement formats security requirement, e.g. "apiKey, oauth (read, write)".
+func requirement(r map[string][]string) string {
+	names := make([]string, 0, len(r))
+
+	for name, scopes := range r {
+		if len(scopes) > 0 {
+			name += " (" + strings.Join(scopes, ", ") + ")"
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+var page = template.Must(template.New("inventory").Funcs(template.FuncMap{
+	"join":        strings.Join,
+	"requirement": requirement,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Routes</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+td ul { margin: 0; padding-left: 16px; }
+.deprecated { text-decoration: line-through; }
+</style>
+</head>
+<body>
+<h1>Routes</h1>
+<table>
+<tr><th>Method</th><th>Pattern</th><th>Use case</th><th>Input</th><th>Output</th><th>Decoders</th><th>Security</th><th>Middlewares</th></tr>
+{{range .}}<tr>
+<td>{{.Method}}</td>
+<td{{if .Deprecated}} class="deprecated"{{end}}>{{.Pattern}}</td>
+<td>{{.Name}}{{if .Title}}<br>{{.Title}}{{end}}{{if .Tags}}<br>[{{join .Tags ", "}}]{{end}}</td>
+<td>{{.Input}}</td>
+<td>{{.Output}}</td>
+<td>{{join .Decoders ", "}}</td>
+<td>{{range $i, $r := .Security}}{{if $i}}<br>{{end}}{{requirement $r}}{{end}}</td>
+<td><ul>{{range .Middlewares}}<li>{{.}}</li>{{end}}{{range .Wraps}}<li>{{.}}</li>{{end}}</ul></td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
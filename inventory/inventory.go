@@ -0,0 +1,208 @@
+package inventory
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	oapi "github.com/swaggest/openapi-go"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/usecase"
+)
+
+// Route describes a route of router.
+type Route struct {
+	Method  string `json:"method"`
+	Pattern string `json:"pattern"`
+
+	// Name, Title, Tags and Deprecated describe use case, they are empty for plain http.Handler.
+	Name       string   `json:"name,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Deprecated bool     `json:"deprecated,omitempty"`
+
+	// Input and Output are Go types of use case ports.
+	Input  string `json:"input,omitempty"`
+	Output string `json:"output,omitempty"`
+
+	// Middlewares are request middlewares of router, outermost first.
+	Middlewares []string `json:"middlewares,omitempty"`
+
+	// Wraps are unwrappable handler middlewares, outermost first.
+	Wraps []string `json:"wraps,omitempty"`
+
+	// Security lists alternative security requirements from OpenAPI document.
+	Security []map[string][]string `json:"security,omitempty"`
+
+	// Decoders lists request parts that are decoded into input, e.g. "body", "path" or "query".
+	Decoders []string `json:"decoders,omitempty"`
+}
+
+// Collect lists routes of chi router, for example web.Service or chirouter.Wrapper.
+//
+// Collector is optional, if set, it is used to describe route security.
+func Collect(routes chi.Routes, c *openapi.Collector) ([]Route, error) {
+	var (
+		res []Route
+		sec security
+	)
+
+	if c != nil {
+		if err := sec.load(c); err != nil {
+			return nil, err
+		}
+	}
+
+	err := chi.Walk(routes, func(method, route string, h http.Handler, mws ...func(http.Handler) http.Handler) error {
+		r := Route{
+			Method:  method,
+			Pattern: route,
+			Wraps:   nethttp.WrapperNames(h),
+		}
+
+		for _, mw := range mws {
+			r.Middlewares = append(r.Middlewares, funcName(mw))
+		}
+
+		var handler *nethttp.Handler
+		if nethttp.HandlerAs(h, &handler) {
+			describeUseCase(&r, handler)
+		}
+
+		r.Security = sec.requirements(method, route)
+
+		res = append(res, r)
+
+		return nil
+	})
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Pattern == res[j].Pattern {
+			return res[i].Method < res[j].Method
+		}
+
+		return res[i].Pattern < res[j].Pattern
+	})
+
+	return res, err
+}
+
+func describeUseCase(r *Route, h *nethttp.Handler) {
+	var (
+		u          = h.UseCase()
+		withName   usecase.HasName
+		withTitle  usecase.HasTitle
+		withTags   usecase.HasTags
+		withInput  usecase.HasInputPort
+		withOutput usecase.HasOutputPort
+	)
+
+	if usecase.As(u, &withName) {
+		r.Name = withName.Name()
+	}
+
+	if usecase.As(u, &withTitle) {
+		r.Title = withTitle.Title()
+	}
+
+	if usecase.As(u, &withTags) {
+		r.Tags = withTags.Tags()
+	}
+
+	_, r.Deprecated = rest.UseCaseDeprecation(u)
+
+	if usecase.As(u, &withInput) && withInput.InputPort() != nil {
+		r.Input = reflect.TypeOf(withInput.InputPort()).String()
+	}
+
+	if d, ok := h.RequestDecoder().(interface{ ParamsIn() []rest.ParamIn }); ok {
+		for _, in := range d.ParamsIn() {
+			r.Decoders = append(r.Decoders, string(in))
+		}
+
+		sort.Strings(r.Decoders)
+	}
+
+	if usecase.As(u, &withOutput) && withOutput.OutputPort() != nil {
+		r.Output = reflect.TypeOf(withOutput.OutputPort()).String()
+	}
+}
+
+func funcName(f interface{}) string {
+	return runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+}
+
+// security reads security requirements from OpenAPI document.
+type security struct {
+	global []map[string][]string
+	paths  map[string]map[string]operationSecurity
+}
+
+type operationSecurity struct {
+	Security *[]map[string][]string `json:"security"`
+}
+
+func (s *security) load(c *openapi.Collector) error {
+	// Cached document is used, marshaling spec directly would race with collection and serving.
+	data, err := c.CompactJSON()
+	if err != nil {
+		return err
+	}
+
+	var doc struct {
+		Security []map[string][]string `json:"security"`
+		Paths    map[string]map[string]json.RawMessage
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	s.global = doc.Security
+	s.paths = make(map[string]map[string]operationSecurity, len(doc.Paths))
+
+	for path, item := range doc.Paths {
+		ops := make(map[string]operationSecurity, len(item))
+
+		for method, raw := range item {
+			op := ops[method]
+
+			// Path item also contains non-operation properties that are skipped.
+			if err := json.Unmarshal(raw, &op); err == nil {
+				ops[method] = op
+			}
+		}
+
+		s.paths[path] = ops
+	}
+
+	return nil
+}
+
+func (s *security) requirements(method, pattern string) []map[string][]string {
+	if s.paths == nil {
+		return nil
+	}
+
+	_, path, _, err := oapi.SanitizeMethodPath(method, pattern)
+	if err != nil {
+		return nil
+	}
+
+	op, found := s.paths[path][strings.ToLower(method)]
+	if !found {
+		return nil
+	}
+
+	if op.Security != nil {
+		return *op.Security
+	}
+
+	return s.global
+}
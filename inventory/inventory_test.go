@@ -0,0 +1,156 @@
+package inventory_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/inventory"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+)
+
+func newService() *web.Service {
+	s := web.NewService(openapi3.NewReflector())
+
+	type getUser struct {
+		ID     int    `path:"id"`
+		Locale string `query:"locale"`
+	}
+
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, _ getUser, _ *user) error { return nil })
+	u.SetName("getUser")
+	u.SetTitle("Get User")
+	u.SetTags("users")
+
+	type createUser struct {
+		Name string `json:"name"`
+	}
+
+	c := usecase.NewInteractor(func(_ context.Context, _ createUser, _ *user) error { return nil })
+	c.SetName("createUser")
+
+	s.Route("/users", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(
+				middleware.NoCache,
+				nethttp.HTTPBearerSecurityMiddleware(s.OpenAPICollector, "Admin", "", ""),
+			)
+			r.Method(http.MethodPost, "/", nethttp.NewHandler(c))
+		})
+
+		r.Method(http.MethodGet, "/{id}", nethttp.NewHandler(u))
+	})
+
+	s.Method(http.MethodGet, "/health", http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	return s
+}
+
+func TestCollect(t *testing.T) {
+	s := newService()
+
+	routes, err := s.RouteInventory()
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `[
+	  {"method":"GET","pattern":"/health","wraps":"<ignore-diff>"},
+	  {
+		"method":"POST","pattern":"/users/","name":"createUser","title":"New Service",
+		"input":"inventory_test.createUser","output":"*inventory_test.user",
+		"middlewares":["github.com/go-chi/chi/v5/middleware.NoCache"],
+		"wraps":"<ignore-diff>","security":[{"Admin":[]}],"decoders":["body"]
+	  },
+	  {
+		"method":"GET","pattern":"/users/{id}","name":"getUser","title":"Get User","tags":["users"],
+		"input":"inventory_test.getUser","output":"*inventory_test.user",
+		"wraps":"<ignore-diff>","decoders":["path","query"]
+	  }
+	]`, routes)
+
+	assert.Contains(t, routes[2].Wraps, "github.com/swaggest/rest/nethttp.OpenAPIMiddleware.func1")
+	assert.Contains(t, routes[2].Wraps, "github.com/swaggest/rest/nethttp.HandlerWithRouteMiddleware.func1")
+
+	routes, err = inventory.Collect(s, nil)
+	require.NoError(t, err)
+	assert.Len(t, routes, 3)
+	assert.Empty(t, routes[1].Security)
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	s := newService()
+	h := inventory.NewHandler(s, s.OpenAPICollector)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/routes", nil)
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+
+	var routes []inventory.Route
+
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &routes))
+	require.Len(t, routes, 3)
+	assert.Equal(t, "/users/{id}", routes[2].Pattern)
+	assert.Equal(t, "getUser", routes[2].Name)
+
+	req = httptest.NewRequest(http.MethodGet, "/debug/routes", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	rw = httptest.NewRecorder()
+
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rw.Header().Get("Content-Type"))
+	assert.Contains(t, rw.Body.String(), "<td>/users/{id}</td>")
+	assert.Contains(t, rw.Body.String(), "<td>Admin</td>")
+	assert.Contains(t, rw.Body.String(), "<td>path, query</td>")
+}
+
+func TestCollect_concurrent(t *testing.T) {
+	s := newService()
+	s.OpenAPICollector.ServerURLFromRequest = true
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			routes, err := inventory.Collect(s, s.OpenAPICollector)
+			assert.NoError(t, err)
+			assert.Equal(t, []map[string][]string{{"Admin": {}}}, routes[1].Security)
+		}()
+
+		go func(i int) {
+			defer wg.Done()
+
+			req := httptest.NewRequest(http.MethodGet, "/docs/openapi.json", nil)
+			req.Host = "example" + strconv.Itoa(i) + ".com"
+
+			s.OpenAPICollector.ServeHTTP(httptest.NewRecorder(), req)
+		}(i)
+	}
+
+	wg.Wait()
+}
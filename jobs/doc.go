@@ -0,0 +1,2 @@
+// Package jobs runs use cases in background and serves status of their jobs.
+package jobs
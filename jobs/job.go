@@ -0,0 +1,124 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/swaggest/rest"
+	"github.com/swaggest/usecase/status"
+)
+
+// Status is a state of job.
+type Status string
+
+// Job statuses.
+const (
+	Pending  = Status("pending")
+	Running  = Status("running")
+	Done     = Status("done")
+	Canceled = Status("canceled")
+)
+
+// IsFinished reports whether job has finished.
+func (s Status) IsFinished() bool {
+	return s == Done || s == Canceled
+}
+
+// Job describes a background interaction of use case.
+type Job struct {
+	ID        string    `json:"id"`
+	Status    Status    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Output is a use case output, it is available when job is done without error.
+	Output interface{} `json:"output,omitempty"`
+
+	// Error is available when job is done with error.
+	Error *rest.ErrResponse `json:"error,omitempty"`
+}
+
+// ErrJobNotFound is returned by Store for unknown job.
+var ErrJobNotFound = status.Wrap(errors.New("job not found"), status.NotFound)
+
+// Store keeps jobs.
+type Store interface {
+	// Put creates or updates a job.
+	Put(ctx context.Context, job Job) error
+
+	// Get returns job by ID or ErrJobNotFound.
+	Get(ctx context.Context, id string) (Job, error)
+}
+
+// MemoryStore keeps jobs in memory.
+//
+// Please use NewMemoryStore to create instance.
+type MemoryStore struct {
+	// Retention is a duration to keep finished jobs, default DefaultRetention, zero value keeps all jobs.
+	// Expired jobs are removed on Put and Get.
+	Retention time.Duration
+
+	mu        sync.Mutex
+	jobs      map[string]Job
+	evictedAt time.Time
+}
+
+// DefaultRetention is a default duration to keep finished jobs in MemoryStore.
+const DefaultRetention = time.Hour
+
+// NewMemoryStore creates in-memory job store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		Retention: DefaultRetention,
+		jobs:      make(map[string]Job),
+	}
+}
+
+// Put creates or updates a job.
+func (m *MemoryStore) Put(_ context.Context, job Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.jobs[job.ID] = job
+	m.evict()
+
+	return nil
+}
+
+// Get returns job by ID.
+func (m *MemoryStore) Get(_ context.Context, id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evict()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+
+	return job, nil
+}
+
+// evict removes expired jobs, it is called with lock held and scans jobs at most once per tenth of Retention.
+func (m *MemoryStore) evict() {
+	if m.Retention <= 0 {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(m.evictedAt) < m.Retention/10 {
+		return
+	}
+
+	m.evictedAt = now
+	expired := now.Add(-m.Retention)
+
+	for id, j := range m.jobs {
+		if j.Status.IsFinished() && j.UpdatedAt.Before(expired) {
+			delete(m.jobs, id)
+		}
+	}
+}
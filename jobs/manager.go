@@ -0,0 +1,508 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// HasIsAsync is implemented by use case that should be served in background.
+type HasIsAsync interface {
+	IsAsync() bool
+}
+
+// Async is a use case middleware that marks use case to be served in background.
+//
+//	u = usecase.Wrap(u, jobs.Async)
+var Async usecase.Middleware = usecase.MiddlewareFunc(func(next usecase.Interactor) usecase.Interactor {
+	return asyncMarker{Interactor: next}
+})
+
+type asyncMarker struct {
+	usecase.Interactor
+}
+
+func (asyncMarker) IsAsync() bool {
+	return true
+}
+
+// Accepted is a response to a request of background use case.
+type Accepted struct {
+	ID       string `json:"id" description:"Job ID."`
+	Status   Status `json:"status" enum:"pending"`
+	Location string `header:"Location" json:"-" description:"URL of job status."`
+}
+
+// Router adds handlers to routes.
+type Router interface {
+	Method(method, pattern string, h http.Handler)
+}
+
+// NewManager creates job manager.
+func NewManager(options ...func(m *Manager)) *Manager {
+	m := Manager{
+		StatusPattern: "/jobs/{jobID}",
+		cancels:       make(map[string]context.CancelFunc),
+		statusRoutes:  make(map[string]bool),
+	}
+
+	for _, o := range options {
+		o(&m)
+	}
+
+	if m.Store == nil {
+		m.Store = NewMemoryStore()
+	}
+
+	return &m
+}
+
+// Manager runs use cases in background and serves status of their jobs.
+//
+// Please use NewManager to create instance.
+type Manager struct {
+	// Store keeps jobs, default NewMemoryStore().
+	Store Store
+
+	// StatusPattern is appended to route pattern of use case to serve job status, default "/jobs/{jobID}".
+	// It must have {jobID} path parameter.
+	StatusPattern string
+
+	mu           sync.Mutex
+	cancels      map[string]context.CancelFunc
+	statusRoutes map[string]bool
+}
+
+// Method adds the route `pattern` that matches `method` http method to router.
+//
+// If handler serves a use case marked with Async, the use case is served in background with 202 Accepted response,
+// and routes to get (GET) and to cancel (DELETE) job are added at pattern + StatusPattern.
+//
+// Async use case can not receive uploaded files, because they are removed when request is served.
+func (m *Manager) Method(r Router, method, pattern string, h http.Handler) {
+	var handler *nethttp.Handler
+
+	if !nethttp.HandlerAs(h, &handler) || !isAsync(handler.UseCase()) {
+		r.Method(method, pattern, h)
+
+		return
+	}
+
+	var withInput usecase.HasInputPort
+	if usecase.As(handler.UseCase(), &withInput) && hasFileFields(reflect.TypeOf(withInput.InputPort())) {
+		panic("async use case can not receive uploaded files: " + method + " " + pattern)
+	}
+
+	m.setupHandler(handler)
+
+	var job asyncJob
+	usecase.As(handler.UseCase(), &job)
+
+	r.Method(method, pattern, nethttp.WrapHandler(h, requestPathMiddleware))
+
+	m.mu.Lock()
+	statusPattern := strings.TrimSuffix(pattern, "/") + m.StatusPattern
+	exists := m.statusRoutes[statusPattern]
+	m.statusRoutes[statusPattern] = true
+	m.mu.Unlock()
+
+	if exists {
+		return
+	}
+
+	u := job.asyncInteractor()
+
+	r.Method(http.MethodGet, statusPattern, nethttp.NewHandler(m.statusUseCase(u, false)))
+	r.Method(http.MethodDelete, statusPattern, nethttp.NewHandler(m.statusUseCase(u, true)))
+}
+
+var (
+	multipartFileType       = reflect.TypeOf((*multipart.File)(nil)).Elem()
+	multipartFileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+)
+
+// hasFileFields checks if input structure has fields to receive uploaded files.
+func hasFileFields(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i).Type
+		if ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+
+		if ft == multipartFileType || ft == multipartFileHeaderType {
+			return true
+		}
+
+		if t.Field(i).Anonymous && hasFileFields(ft) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isAsync(u usecase.Interactor) bool {
+	var hasIsAsync HasIsAsync
+
+	return usecase.As(u, &hasIsAsync) && hasIsAsync.IsAsync()
+}
+
+// setupHandler replaces use case of handler with one that starts a job.
+func (m *Manager) setupHandler(h *nethttp.Handler) {
+	h.SuccessStatus = http.StatusAccepted
+	h.SetUseCase(usecase.Wrap(h.UseCase(), usecase.MiddlewareFunc(func(next usecase.Interactor) usecase.Interactor {
+		u := &asyncInteractor{
+			useCase: next,
+			manager: m,
+		}
+
+		var withOutput usecase.HasOutputPort
+		if usecase.As(next, &withOutput) {
+			u.outputType = reflect.TypeOf(withOutput.OutputPort())
+		}
+
+		if u.outputType != nil && u.outputType.Kind() == reflect.Ptr {
+			u.outputType = u.outputType.Elem()
+		}
+
+		return u
+	})))
+}
+
+type asyncJob interface {
+	asyncInteractor() *asyncInteractor
+}
+
+// asyncInteractor starts a job of use case and exposes Accepted as output port.
+type asyncInteractor struct {
+	useCase    usecase.Interactor
+	manager    *Manager
+	outputType reflect.Type
+}
+
+func (u *asyncInteractor) asyncInteractor() *asyncInteractor {
+	return u
+}
+
+// IsAsync implements HasIsAsync, use case is already served in background, so it is not async anymore.
+func (u *asyncInteractor) IsAsync() bool {
+	return false
+}
+
+// OutputPort implements usecase.HasOutputPort.
+func (u *asyncInteractor) OutputPort() interface{} {
+	return new(Accepted)
+}
+
+// Interact starts a job and fills Accepted output.
+func (u *asyncInteractor) Interact(ctx context.Context, input, output interface{}) error {
+	accepted, ok := output.(*Accepted)
+	if !ok {
+		return errors.New("unexpected output type")
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	job := Job{ID: id, Status: Pending, CreatedAt: now, UpdatedAt: now}
+
+	if err := u.manager.Store.Put(ctx, job); err != nil {
+		return err
+	}
+
+	jobCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+
+	u.manager.mu.Lock()
+	u.manager.cancels[id] = cancel
+	u.manager.mu.Unlock()
+
+	go u.manager.run(jobCtx, u, job, input)
+
+	accepted.ID = id
+	accepted.Status = Pending
+
+	if p, ok := ctx.Value(requestPathCtxKey{}).(string); ok {
+		accepted.Location = strings.TrimSuffix(p, "/") +
+			strings.Replace(u.manager.StatusPattern, "{jobID}", id, 1)
+	}
+
+	return nil
+}
+
+func (m *Manager) run(ctx context.Context, u *asyncInteractor, job Job, input interface{}) {
+	defer func() {
+		m.mu.Lock()
+		cancel := m.cancels[job.ID]
+		delete(m.cancels, job.ID)
+		m.mu.Unlock()
+
+		if cancel != nil {
+			cancel()
+		}
+	}()
+
+	job.Status = Running
+	job.UpdatedAt = time.Now()
+
+	if err := m.Store.Put(ctx, job); err != nil {
+		m.finish(ctx, job, nil, err)
+
+		return
+	}
+
+	var output interface{}
+	if u.outputType != nil {
+		output = reflect.New(u.outputType).Interface()
+	}
+
+	err := u.useCase.Interact(ctx, input, output)
+
+	m.finish(ctx, job, output, err)
+}
+
+func (m *Manager) finish(ctx context.Context, job Job, output interface{}, err error) {
+	job.UpdatedAt = time.Now()
+
+	switch {
+	case ctx.Err() != nil:
+		job.Status = Canceled
+	case err != nil:
+		_, er := rest.Err(err)
+		job.Status = Done
+		job.Error = &er
+	default:
+		job.Status = Done
+		job.Output = output
+	}
+
+	// Job context is canceled at this point, so detached context is used to store result.
+	_ = m.Store.Put(detachedContext{parent: ctx}, job) //nolint:errcheck // Result can not be reported.
+}
+
+// Cancel cancels running job.
+func (m *Manager) Cancel(ctx context.Context, id string) (Job, error) {
+	job, err := m.Store.Get(ctx, id)
+	if err != nil {
+		return job, err
+	}
+
+	m.mu.Lock()
+	cancel := m.cancels[id]
+	m.mu.Unlock()
+
+	if cancel == nil || job.Status.IsFinished() {
+		return job, nil
+	}
+
+	cancel()
+
+	job.Status = Canceled
+	job.UpdatedAt = time.Now()
+
+	return job, m.Store.Put(ctx, job)
+}
+
+type jobInput struct {
+	ID string `path:"jobID" description:"Job ID."`
+}
+
+// statusUseCase creates use case to get or cancel job, output of use case has output type of async use case.
+func (m *Manager) statusUseCase(u *asyncInteractor, cancel bool) usecase.Interactor {
+	statusType := jobStatusType(u.outputType)
+
+	uc := usecase.NewIOI(new(jobInput), reflect.New(statusType).Interface(),
+		func(ctx context.Context, input, output interface{}) error {
+			var (
+				in  = input.(*jobInput) //nolint:errcheck // Type is ensured by input port.
+				job Job
+				err error
+			)
+
+			if cancel {
+				job, err = m.Cancel(ctx, in.ID)
+			} else {
+				job, err = m.Store.Get(ctx, in.ID)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			return fillStatus(reflect.ValueOf(output).Elem(), job)
+		})
+
+	var (
+		hasName  usecase.HasName
+		hasTitle usecase.HasTitle
+		hasTags  usecase.HasTags
+		name     string
+		title    string
+	)
+
+	if usecase.As(u.useCase, &hasName) {
+		name = hasName.Name()
+	}
+
+	if usecase.As(u.useCase, &hasTitle) {
+		title = hasTitle.Title()
+	}
+
+	if title == "" {
+		title = name
+	}
+
+	if usecase.As(u.useCase, &hasTags) {
+		uc.SetTags(hasTags.Tags()...)
+	}
+
+	if cancel {
+		uc.SetTitle(strings.TrimSpace("Cancel job " + title))
+		uc.SetExpectedErrors(status.NotFound)
+
+		if name != "" {
+			uc.SetName(name + "CancelJob")
+		}
+	} else {
+		uc.SetTitle(strings.TrimSpace("Get job " + title))
+		uc.SetExpectedErrors(status.NotFound)
+
+		if name != "" {
+			uc.SetName(name + "Job")
+		}
+	}
+
+	return uc
+}
+
+// jobStatusType makes a struct type similar to Job with typed output.
+func jobStatusType(outputType reflect.Type) reflect.Type {
+	var jobType = reflect.TypeOf(Job{})
+
+	fields := make([]reflect.StructField, 0, jobType.NumField())
+
+	for i := 0; i < jobType.NumField(); i++ {
+		f := jobType.Field(i)
+
+		switch f.Name {
+		case "Status":
+			f.Tag += ` enum:"pending,running,done,canceled"`
+		case "Output":
+			if outputType == nil {
+				continue
+			}
+
+			f.Type = reflect.PtrTo(outputType)
+		}
+
+		fields = append(fields, f)
+	}
+
+	return reflect.StructOf(fields)
+}
+
+// fillStatus copies job into a value of jobStatusType.
+func fillStatus(v reflect.Value, job Job) error {
+	jv := reflect.ValueOf(job)
+
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		fv := v.Field(i)
+		src := jv.FieldByName(name)
+
+		if name != "Output" {
+			fv.Set(src)
+
+			continue
+		}
+
+		if job.Output == nil {
+			continue
+		}
+
+		out := reflect.ValueOf(job.Output)
+		if out.Type().AssignableTo(fv.Type()) {
+			fv.Set(out)
+
+			continue
+		}
+
+		// Store may provide output in a different form, e.g. after JSON round trip.
+		j, err := json.Marshal(job.Output)
+		if err != nil {
+			return err
+		}
+
+		ptr := reflect.New(fv.Type().Elem())
+		if err := json.Unmarshal(j, ptr.Interface()); err != nil {
+			return err
+		}
+
+		fv.Set(ptr)
+	}
+
+	return nil
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+type requestPathCtxKey struct{}
+
+// requestPathMiddleware makes request path available to use case to build job location.
+func requestPathMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), requestPathCtxKey{}, r.URL.Path)))
+	})
+}
+
+// detachedContext keeps values of parent context, but is not canceled with it.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
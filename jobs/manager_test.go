@@ -0,0 +1,289 @@
+package jobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/jobs"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+)
+
+func TestManager_Method(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector(), func(s *web.Service) {
+		s.Jobs = jobs.NewManager()
+	})
+
+	type reportInput struct {
+		Name string `json:"name" minLength:"1"`
+	}
+
+	type reportOutput struct {
+		Rows int `json:"rows"`
+	}
+
+	release := make(chan struct{})
+
+	u := usecase.NewInteractor(func(ctx context.Context, in reportInput, out *reportOutput) error {
+		if in.Name == "slow" {
+			<-ctx.Done()
+
+			return ctx.Err()
+		}
+
+		<-release
+
+		if in.Name == "fail" {
+			return errors.New("failed")
+		}
+
+		out.Rows = len(in.Name)
+
+		return nil
+	})
+	u.SetName("buildReport")
+	u.SetTitle("Build Report")
+	u.SetTags("Reports")
+
+	s.Post("/reports", usecase.Wrap(u, jobs.Async))
+
+	post := func(body string) (int, string, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/reports", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rw := httptest.NewRecorder()
+		s.ServeHTTP(rw, req)
+
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+
+		return rw.Code, rw.Header().Get("Location"), resp
+	}
+
+	call := func(method, location string) (int, []byte) {
+		req := httptest.NewRequest(method, location, nil)
+		rw := httptest.NewRecorder()
+		s.ServeHTTP(rw, req)
+
+		return rw.Code, rw.Body.Bytes()
+	}
+
+	poll := func(location string) []byte {
+		for i := 0; i < 100; i++ {
+			code, body := call(http.MethodGet, location)
+			require.Equal(t, http.StatusOK, code, string(body))
+
+			var j jobs.Job
+			require.NoError(t, json.Unmarshal(body, &j))
+
+			if j.Status.IsFinished() {
+				return body
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		t.Fatal("job is not finished")
+
+		return nil
+	}
+
+	code, _, resp := post(`{"name":""}`)
+	assert.Equal(t, http.StatusBadRequest, code, resp)
+
+	code, location, resp := post(`{"name":"report"}`)
+	assert.Equal(t, http.StatusAccepted, code, resp)
+	assert.Equal(t, "/reports/jobs/"+resp["id"].(string), location)
+	assert.Equal(t, "pending", resp["status"])
+
+	_, failLocation, _ := post(`{"name":"fail"}`)
+
+	close(release)
+
+	assertjson.Equal(t, []byte(`{
+	  "id":"<ignore-diff>","status":"done","createdAt":"<ignore-diff>","updatedAt":"<ignore-diff>",
+	  "output":{"rows":6}
+	}`), poll(location))
+
+	assertjson.Equal(t, []byte(`{
+	  "id":"<ignore-diff>","status":"done","createdAt":"<ignore-diff>","updatedAt":"<ignore-diff>",
+	  "error":{"error":"failed"}
+	}`), poll(failLocation))
+
+	_, slowLocation, _ := post(`{"name":"slow"}`)
+
+	code, body := call(http.MethodDelete, slowLocation)
+	assert.Equal(t, http.StatusOK, code, string(body))
+	assertjson.Equal(t, []byte(`{
+	  "id":"<ignore-diff>","status":"canceled","createdAt":"<ignore-diff>","updatedAt":"<ignore-diff>"
+	}`), body)
+	assertjson.Equal(t, body, poll(slowLocation), "<ignore-diff>")
+
+	code, body = call(http.MethodGet, "/reports/jobs/unknown")
+	assert.Equal(t, http.StatusNotFound, code)
+	assertjson.Equal(t, []byte(`{"status":"NOT_FOUND","error":"not found: job not found"}`), body)
+
+	j, err := json.Marshal(s.OpenAPISchema())
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "/reports":{
+		"post":{
+		  "tags":["Reports"],"summary":"Build Report","operationId":"buildReport",
+		  "requestBody":"<ignore-diff>",
+		  "responses":{
+			"202":{
+			  "description":"Accepted",
+			  "headers":{"Location":{"style":"simple","description":"URL of job status.","schema":{"type":"string","description":"URL of job status."}}},
+			  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/JobsAccepted"}}}
+			}
+		  }
+		}
+	  },
+	  "/reports/jobs/{jobID}":{
+		"get":{
+		  "tags":["Reports"],"summary":"Get job Build Report","operationId":"buildReportJob",
+		  "parameters":"<ignore-diff>",
+		  "responses":{
+			"200":{
+			  "description":"OK",
+			  "content":{"application/json":{"schema":{
+				"type":"object",
+				"properties":{
+				  "id":{"type":"string"},
+				  "status":{"enum":["pending","running","done","canceled"],"type":"string"},
+				  "createdAt":{"type":"string","format":"date-time"},
+				  "updatedAt":{"type":"string","format":"date-time"},
+				  "output":{"$ref":"#/components/schemas/JobsTestReportOutput"},
+				  "error":{"$ref":"#/components/schemas/RestErrResponse"}
+				}
+			  }}}
+			},
+			"404":"<ignore-diff>"
+		  }
+		},
+		"delete":"<ignore-diff>"
+	  }
+	}`, json.RawMessage(mustPaths(t, j)))
+}
+
+func mustPaths(t *testing.T, spec []byte) []byte {
+	t.Helper()
+
+	var s struct {
+		Paths json.RawMessage `json:"paths"`
+	}
+
+	require.NoError(t, json.Unmarshal(spec, &s))
+
+	return s.Paths
+}
+
+func TestManager_Method_subrouter(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector(), func(s *web.Service) {
+		s.Jobs = jobs.NewManager()
+	})
+
+	u := usecase.NewInteractor(func(_ context.Context, _ struct{}, out *int) error {
+		*out = 1
+
+		return nil
+	})
+	u.SetName("buildReport")
+
+	s.Route("/v1", func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Subrouter", "v1")
+				next.ServeHTTP(w, r)
+			})
+		})
+
+		r.Method(http.MethodPost, "/reports", nethttp.NewHandler(usecase.Wrap(u, jobs.Async)))
+	})
+
+	s.With().Method(http.MethodPost, "/other", nethttp.NewHandler(usecase.Wrap(u, jobs.Async)))
+
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/v1/reports", nil))
+	assert.Equal(t, http.StatusAccepted, rw.Code, rw.Body.String())
+
+	location := rw.Header().Get("Location")
+	assert.True(t, strings.HasPrefix(location, "/v1/reports/jobs/"), location)
+
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, location, nil))
+	assert.Equal(t, http.StatusOK, rw.Code, rw.Body.String())
+	assert.Equal(t, "v1", rw.Header().Get("X-Subrouter"))
+
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/other", nil))
+	assert.Equal(t, http.StatusAccepted, rw.Code, rw.Body.String())
+
+	j, err := json.Marshal(s.OpenAPISchema())
+	require.NoError(t, err)
+	assert.Contains(t, string(mustPaths(t, j)), `"/v1/reports/jobs/{jobID}"`)
+	assert.Contains(t, string(mustPaths(t, j)), `"/other/jobs/{jobID}"`)
+
+	// Async use case can not be served without jobs manager.
+	s = web.NewService(openapi3.NewReflector())
+
+	assert.PanicsWithValue(t, "async use case handler requires EnableAsync: POST /v1/reports", func() {
+		s.Route("/v1", func(r chi.Router) {
+			r.Method(http.MethodPost, "/reports", nethttp.NewHandler(usecase.Wrap(u, jobs.Async)))
+		})
+	})
+}
+
+func TestMemoryStore_Retention(t *testing.T) {
+	ctx := context.Background()
+	m := jobs.NewMemoryStore()
+	assert.Equal(t, jobs.DefaultRetention, m.Retention)
+
+	m.Retention = 10 * time.Millisecond
+
+	now := time.Now()
+	require.NoError(t, m.Put(ctx, jobs.Job{ID: "done", Status: jobs.Done, UpdatedAt: now}))
+	require.NoError(t, m.Put(ctx, jobs.Job{ID: "running", Status: jobs.Running, UpdatedAt: now}))
+
+	_, err := m.Get(ctx, "done")
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = m.Get(ctx, "done")
+	assert.ErrorIs(t, err, jobs.ErrJobNotFound)
+
+	_, err = m.Get(ctx, "running")
+	require.NoError(t, err)
+}
+
+func TestManager_Method_files(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector(), func(s *web.Service) {
+		s.Jobs = jobs.NewManager()
+	})
+
+	type upload struct {
+		File *multipart.FileHeader `formData:"file"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, _ upload, _ *struct{}) error {
+		return nil
+	})
+
+	assert.PanicsWithValue(t, "async use case can not receive uploaded files: POST /upload", func() {
+		s.Post("/upload", usecase.Wrap(u, jobs.Async))
+	})
+}
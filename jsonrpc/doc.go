@@ -0,0 +1,2 @@
+// Package jsonrpc serves use case interactors with JSON-RPC 2.0 and documents them with OpenRPC.
+package jsonrpc
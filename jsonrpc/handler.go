@@ -0,0 +1,451 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"github.com/swaggest/rest"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// Version is a version of JSON-RPC protocol.
+const Version = "2.0"
+
+// Standard error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	// CodeServerError is a base for codes of canonical statuses other than InvalidArgument and Internal,
+	// for example status.NotFound (5) has code -32005.
+	CodeServerError = -32000
+)
+
+// Request is a JSON-RPC request object.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+
+	// ID is empty for notifications.
+	ID json.RawMessage `json:"id,omitempty"`
+}
+
+// Response is a JSON-RPC response object.
+type Response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	Result  *json.RawMessage `json:"result,omitempty"`
+	Error   *Error           `json:"error,omitempty"`
+	ID      json.RawMessage  `json:"id"`
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrorCode returns JSON-RPC error code for canonical status of error.
+func ErrorCode(err error) int {
+	var withCanonicalStatus rest.ErrWithCanonicalStatus
+
+	if !errors.As(err, &withCanonicalStatus) {
+		return CodeInternalError
+	}
+
+	switch st := withCanonicalStatus.Status(); st {
+	case status.InvalidArgument:
+		return CodeInvalidParams
+	case status.Internal, status.Unknown:
+		return CodeInternalError
+	default:
+		return CodeServerError - int(st)
+	}
+}
+
+// NewHandler creates JSON-RPC handler.
+func NewHandler(options ...func(h *Handler)) *Handler {
+	h := Handler{
+		MaxBodySize: 1 << 20,
+		MaxItems:    20,
+		Concurrency: 4,
+		methods:     make(map[string]*method),
+	}
+
+	h.OpenRPC = NewDocument()
+
+	for _, o := range options {
+		o(&h)
+	}
+
+	return &h
+}
+
+// Handler serves use case interactors by their names with JSON-RPC 2.0 over HTTP POST.
+//
+// Use case input is decoded from params by name (JSON object), input fields are mapped with `json` tags.
+//
+// Please use NewHandler to create instance.
+type Handler struct {
+	// Validator makes validators of params, for example jsonschema.NewFactory(collector, collector).
+	// Params are not validated if Validator is nil.
+	Validator rest.RequestValidatorFactory
+
+	// OpenRPC is a document of added methods, it can be served as http.Handler.
+	OpenRPC *Document
+
+	// MaxBodySize limits size of request body in bytes, default 1 MiB.
+	MaxBodySize int64
+
+	// MaxItems limits number of requests in a batch, default 20.
+	MaxItems int
+
+	// Concurrency limits number of batch requests served at the same time, default 4.
+	Concurrency int
+
+	// HandleInternalError receives recovered panics of methods, default is log.Println.
+	HandleInternalError func(r *http.Request, err error)
+
+	mu      sync.RWMutex
+	methods map[string]*method
+}
+
+type method struct {
+	u          usecase.Interactor
+	inputType  reflect.Type
+	inputIsPtr bool
+	outputType reflect.Type
+	validator  rest.Validator
+}
+
+// Add adds use case interactor as a method, use case must have a name.
+func (h *Handler) Add(u usecase.Interactor) {
+	var hasName usecase.HasName
+
+	if !usecase.As(u, &hasName) || hasName.Name() == "" {
+		panic("use case name is required to add JSON-RPC method")
+	}
+
+	name := hasName.Name()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.methods[name]; ok {
+		panic("duplicate JSON-RPC method: " + name)
+	}
+
+	m := method{u: u}
+
+	var (
+		withInput  usecase.HasInputPort
+		withOutput usecase.HasOutputPort
+	)
+
+	if usecase.As(u, &withInput) && withInput.InputPort() != nil {
+		m.inputType = reflect.TypeOf(withInput.InputPort())
+		if m.inputType.Kind() == reflect.Ptr {
+			m.inputType = m.inputType.Elem()
+			m.inputIsPtr = true
+		}
+
+		if h.Validator != nil {
+			m.validator = h.Validator.MakeRequestValidator(http.MethodPost, withInput.InputPort(), nil)
+		}
+	}
+
+	if usecase.As(u, &withOutput) && withOutput.OutputPort() != nil {
+		m.outputType = reflect.TypeOf(withOutput.OutputPort())
+		if m.outputType.Kind() == reflect.Ptr {
+			m.outputType = m.outputType.Elem()
+		}
+	}
+
+	if err := h.OpenRPC.addMethod(u); err != nil {
+		panic(err)
+	}
+
+	h.methods[name] = &m
+}
+
+// ServeHTTP serves JSON-RPC request or batch.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(rw, r.Body, h.MaxBodySize))
+	if err != nil {
+		writeJSON(rw, newErrorResponse(nil, &Error{Code: CodeInvalidRequest, Message: err.Error()}))
+
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+
+	if len(body) == 0 || body[0] != '[' {
+		if resp := h.serveRaw(r, body); resp != nil {
+			writeJSON(rw, resp)
+		} else {
+			rw.WriteHeader(http.StatusNoContent)
+		}
+
+		return
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		writeJSON(rw, newErrorResponse(nil, &Error{Code: CodeParseError, Message: err.Error()}))
+
+		return
+	}
+
+	if len(batch) == 0 {
+		writeJSON(rw, newErrorResponse(nil, &Error{Code: CodeInvalidRequest, Message: "empty batch"}))
+
+		return
+	}
+
+	if len(batch) > h.MaxItems {
+		writeJSON(rw, newErrorResponse(nil, &Error{Code: CodeInvalidRequest, Message: "too many requests in batch"}))
+
+		return
+	}
+
+	responses := make([]*Response, len(batch))
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, h.Concurrency)
+	)
+
+	for i, req := range batch {
+		i, req := i, req
+
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			responses[i] = h.serveRaw(r, req)
+		}()
+	}
+
+	wg.Wait()
+
+	res := make([]*Response, 0, len(responses))
+
+	for _, resp := range responses {
+		if resp != nil {
+			res = append(res, resp)
+		}
+	}
+
+	// Batch of notifications has no response.
+	if len(res) == 0 {
+		rw.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	writeJSON(rw, res)
+}
+
+// serveRaw serves a single request, nil response is returned for notification.
+func (h *Handler) serveRaw(r *http.Request, data []byte) *Response {
+	var req Request
+
+	if err := json.Unmarshal(data, &req); err != nil {
+		var se *json.SyntaxError
+		if errors.As(err, &se) || len(data) == 0 {
+			return newErrorResponse(nil, &Error{Code: CodeParseError, Message: "parse error"})
+		}
+
+		return newErrorResponse(nil, &Error{Code: CodeInvalidRequest, Message: err.Error()})
+	}
+
+	if req.JSONRPC != Version || req.Method == "" || !validID(req.ID) {
+		return newErrorResponse(req.ID, &Error{Code: CodeInvalidRequest, Message: "invalid request"})
+	}
+
+	result, rpcErr := h.call(r, req)
+
+	if len(req.ID) == 0 {
+		return nil
+	}
+
+	if rpcErr != nil {
+		return newErrorResponse(req.ID, rpcErr)
+	}
+
+	return &Response{JSONRPC: Version, Result: &result, ID: req.ID}
+}
+
+// handleInternalError reports error with HandleInternalError, default is log.Println.
+func (h *Handler) handleInternalError(r *http.Request, err error) {
+	if h.HandleInternalError != nil {
+		h.HandleInternalError(r, err)
+	} else {
+		log.Println(err)
+	}
+}
+
+func validID(id json.RawMessage) bool {
+	if len(id) == 0 {
+		return true
+	}
+
+	switch id[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	default:
+		return false
+	}
+}
+
+func (h *Handler) call(r *http.Request, req Request) (res json.RawMessage, rpcErr *Error) {
+	// Panic is recovered to serve other requests of batch, batch requests are served in separate goroutines.
+	defer func() {
+		if rec := recover(); rec != nil {
+			h.handleInternalError(r, fmt.Errorf("jsonrpc: panic in %s: %v\n%s", req.Method, rec, debug.Stack()))
+
+			res, rpcErr = nil, &Error{Code: CodeInternalError, Message: "internal error"}
+		}
+	}()
+
+	if req.Method == discoverMethod {
+		res, err := json.Marshal(h.OpenRPC)
+		if err != nil {
+			return nil, &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+
+		return res, nil
+	}
+
+	h.mu.RLock()
+	m, found := h.methods[req.Method]
+	h.mu.RUnlock()
+
+	if !found {
+		return nil, &Error{Code: CodeMethodNotFound, Message: "method not found"}
+	}
+
+	input, rpcErr := m.decode(req.Params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	var output interface{}
+	if m.outputType != nil {
+		output = reflect.New(m.outputType).Interface()
+	}
+
+	if err := m.u.Interact(r.Context(), input, output); err != nil {
+		return nil, errorObject(err)
+	}
+
+	res, err := json.Marshal(output)
+	if err != nil {
+		return nil, &Error{Code: CodeInternalError, Message: err.Error()}
+	}
+
+	return res, nil
+}
+
+func (m *method) decode(params json.RawMessage) (interface{}, *Error) {
+	if m.inputType == nil {
+		return nil, nil
+	}
+
+	params = bytes.TrimSpace(params)
+
+	if len(params) == 0 || string(params) == "null" {
+		params = []byte("{}")
+	}
+
+	if params[0] != '{' {
+		return nil, &Error{Code: CodeInvalidParams, Message: "params must be an object"}
+	}
+
+	if m.validator != nil {
+		if err := m.validator.ValidateJSONBody(params); err != nil {
+			return nil, errorObject(status.Wrap(err, status.InvalidArgument))
+		}
+	}
+
+	input := reflect.New(m.inputType)
+
+	if err := json.Unmarshal(params, input.Interface()); err != nil {
+		return nil, errorObject(status.Wrap(err, status.InvalidArgument))
+	}
+
+	if m.inputIsPtr {
+		return input.Interface(), nil
+	}
+
+	return input.Elem().Interface(), nil
+}
+
+// errorObject makes JSON-RPC error with rest.ErrResponse as data.
+func errorObject(err error) *Error {
+	_, er := rest.Err(err)
+
+	e := Error{
+		Code:    ErrorCode(err),
+		Message: er.Error(),
+	}
+
+	er.ErrorText = ""
+
+	if er.StatusText != "" || er.AppCode != 0 || len(er.Context) > 0 {
+		e.Data = er
+	}
+
+	return &e
+}
+
+func newErrorResponse(id json.RawMessage, err *Error) *Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+
+	return &Response{JSONRPC: Version, Error: err, ID: id}
+}
+
+func writeJSON(rw http.ResponseWriter, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write(data)
+}
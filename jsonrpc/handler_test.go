@@ -0,0 +1,265 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/jsonrpc"
+	"github.com/swaggest/rest/jsonschema"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	c := openapi.NewCollector(openapi3.NewReflector())
+
+	h := jsonrpc.NewHandler(func(h *jsonrpc.Handler) {
+		h.Validator = jsonschema.NewFactory(c, c)
+		h.OpenRPC.Info.Title = "Calculator"
+		h.OpenRPC.Info.Version = "v1"
+	})
+
+	type divInput struct {
+		A int `json:"a" required:"true" description:"Dividend."`
+		B int `json:"b" required:"true" minimum:"1"`
+	}
+
+	type divOutput struct {
+		Result int `json:"result"`
+	}
+
+	div := usecase.NewInteractor(func(_ context.Context, in divInput, out *divOutput) error {
+		if in.A < 0 {
+			return status.Wrap(errors.New("negative dividend"), status.FailedPrecondition)
+		}
+
+		out.Result = in.A / in.B
+
+		return nil
+	})
+	div.SetName("divide")
+	div.SetTitle("Divide")
+	div.SetTags("Math")
+	div.SetExpectedErrors(status.InvalidArgument, status.FailedPrecondition)
+
+	notified := make(chan string, 10)
+
+	type pingInput struct {
+		Msg string `json:"msg"`
+	}
+
+	ping := usecase.NewInteractor(func(_ context.Context, in pingInput, _ *struct{}) error {
+		notified <- in.Msg
+
+		return nil
+	})
+	ping.SetName("ping")
+
+	h.Add(div)
+	h.Add(ping)
+
+	assert.Panics(t, func() {
+		h.Add(div)
+	})
+
+	call := func(body string) (int, []byte) {
+		req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		return rw.Code, rw.Body.Bytes()
+	}
+
+	code, body := call(`{"jsonrpc":"2.0","method":"divide","params":{"a":7,"b":2},"id":1}`)
+	assert.Equal(t, http.StatusOK, code)
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","result":{"result":3},"id":1}`), body)
+
+	code, body = call(`{"jsonrpc":"2.0","method":"divide","params":{"a":7,"b":0},"id":"x"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","error":{
+	  "code":-32602,"message":"invalid argument: validation failed",
+	  "data":{"status":"INVALID_ARGUMENT","context":{"body":["#/b: must be >= 1/1 but found 0"]}}
+	},"id":"x"}`), body)
+
+	code, body = call(`{"jsonrpc":"2.0","method":"divide","params":{"a":-1,"b":1},"id":2}`)
+	assert.Equal(t, http.StatusOK, code)
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","error":{
+	  "code":-32009,"message":"failed precondition: negative dividend","data":{"status":"FAILED_PRECONDITION"}
+	},"id":2}`), body)
+
+	code, body = call(`{"jsonrpc":"2.0","method":"ping","params":{"msg":"hi"}}`)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Empty(t, body)
+	assert.Equal(t, "hi", <-notified)
+
+	code, body = call(`{"jsonrpc":"2.0","method":"div`)
+	assert.Equal(t, http.StatusOK, code)
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","error":{"code":-32700,"message":"parse error"},"id":null}`), body)
+
+	code, body = call(`[
+	  {"jsonrpc":"2.0","method":"divide","params":{"a":9,"b":3},"id":1},
+	  {"jsonrpc":"2.0","method":"ping","params":{"msg":"batch"}},
+	  {"jsonrpc":"2.0","method":"unknown","id":2},
+	  {"jsonrpc":"1.0","method":"divide","id":3},
+	  1
+	]`)
+	assert.Equal(t, http.StatusOK, code)
+	assertjson.Equal(t, []byte(`[
+	  {"jsonrpc":"2.0","result":{"result":3},"id":1},
+	  {"jsonrpc":"2.0","error":{"code":-32601,"message":"method not found"},"id":2},
+	  {"jsonrpc":"2.0","error":{"code":-32600,"message":"invalid request"},"id":3},
+	  {"jsonrpc":"2.0","error":{"code":-32600,"message":"<ignore-diff>"},"id":null}
+	]`), body)
+	assert.Equal(t, "batch", <-notified)
+
+	code, _ = call(`[{"jsonrpc":"2.0","method":"ping","params":{"msg":"a"}}]`)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, "a", <-notified)
+
+	code, body = call(`[]`)
+	assert.Equal(t, http.StatusOK, code)
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","error":{"code":-32600,"message":"empty batch"},"id":null}`), body)
+
+	code, body = call(`{"jsonrpc":"2.0","method":"rpc.discover","id":1}`)
+	assert.Equal(t, http.StatusOK, code)
+
+	var discovered struct {
+		Result json.RawMessage `json:"result"`
+	}
+
+	require.NoError(t, json.Unmarshal(body, &discovered))
+
+	assertjson.EqMarshal(t, `{
+	  "openrpc":"1.2.6","info":{"title":"Calculator","version":"v1"},
+	  "methods":[
+		{
+		  "name":"divide","summary":"Divide","tags":[{"name":"Math"}],"paramStructure":"by-name",
+		  "params":[
+			{"name":"a","description":"Dividend.","required":true,"schema":{"description":"Dividend.","type":"integer"}},
+			{"name":"b","required":true,"schema":{"minimum":1,"type":"integer"}}
+		  ],
+		  "result":{"name":"result","schema":{"properties":{"result":{"type":"integer"}},"type":"object"}},
+		  "errors":[
+			{"code":-32602,"message":"invalid argument"},
+			{"code":-32009,"message":"failed precondition"}
+		  ]
+		},
+		{
+		  "name":"ping","summary":"Test Handler _ Serve HTTP","paramStructure":"by-name",
+		  "params":[{"name":"msg","schema":{"type":"string"}}],
+		  "result":{"name":"result","schema":{"type":"object"}}
+		}
+	  ],
+	  "components":{}
+	}`, discovered.Result)
+
+	rw := httptest.NewRecorder()
+	h.OpenRPC.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/openrpc.json", nil))
+	assertjson.Equal(t, discovered.Result, rw.Body.Bytes())
+}
+
+func TestHandler_ServeHTTP_limits(t *testing.T) {
+	var internalErrors []string
+
+	h := jsonrpc.NewHandler(func(h *jsonrpc.Handler) {
+		h.MaxBodySize = 200
+		h.MaxItems = 2
+		h.Concurrency = 1
+		h.HandleInternalError = func(r *http.Request, err error) {
+			assert.Equal(t, "/rpc", r.URL.Path)
+
+			internalErrors = append(internalErrors, strings.SplitN(err.Error(), "\n", 2)[0])
+		}
+	})
+
+	fail := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error {
+		panic("failed")
+	})
+	fail.SetName("fail")
+
+	ok := usecase.NewInteractor(func(_ context.Context, _ struct{}, out *struct {
+		OK bool `json:"ok"`
+	},
+	) error {
+		out.OK = true
+
+		return nil
+	})
+	ok.SetName("ok")
+
+	h.Add(fail)
+	h.Add(ok)
+
+	call := func(body string) []byte {
+		req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		assert.Equal(t, http.StatusOK, rw.Code)
+
+		return rw.Body.Bytes()
+	}
+
+	assertjson.Equal(t, []byte(`[
+	  {"jsonrpc":"2.0","error":{"code":-32603,"message":"internal error"},"id":1},
+	  {"jsonrpc":"2.0","result":{"ok":true},"id":2}
+	]`), call(`[{"jsonrpc":"2.0","method":"fail","id":1},{"jsonrpc":"2.0","method":"ok","id":2}]`))
+
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","error":{"code":-32603,"message":"internal error"},"id":1}`),
+		call(`{"jsonrpc":"2.0","method":"fail","id":1}`))
+	assert.Equal(t, []string{"jsonrpc: panic in fail: failed", "jsonrpc: panic in fail: failed"}, internalErrors)
+
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","error":{"code":-32600,"message":"too many requests in batch"},"id":null}`),
+		call(`[{"jsonrpc":"2.0","method":"ok","id":1},{"jsonrpc":"2.0","method":"ok","id":2},{"jsonrpc":"2.0","method":"ok","id":3}]`))
+
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","error":{"code":-32600,"message":"http: request body too large"},"id":null}`),
+		call(`{"jsonrpc":"2.0","method":"ok","params":{"pad":"`+strings.Repeat("x", 200)+`"},"id":1}`))
+}
+
+func TestHandler_Add_concurrent(t *testing.T) {
+	h := jsonrpc.NewHandler()
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 10; i++ {
+		u := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error {
+			return nil
+		})
+		u.SetName("m" + strconv.Itoa(i))
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			h.Add(u)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(`{"jsonrpc":"2.0","method":"m0","id":1}`))
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		}()
+	}
+
+	wg.Wait()
+
+	req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(`{"jsonrpc":"2.0","method":"m9","id":1}`))
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assertjson.Equal(t, []byte(`{"jsonrpc":"2.0","result":{},"id":1}`), rw.Body.Bytes())
+}
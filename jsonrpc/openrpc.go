@@ -0,0 +1,233 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/usecase"
+)
+
+// discoverMethod is a service discovery method defined by OpenRPC.
+const discoverMethod = "rpc.discover"
+
+// OpenRPCVersion is a version of OpenRPC specification.
+const OpenRPCVersion = "1.2.6"
+
+// NewDocument creates OpenRPC document.
+func NewDocument() *Document {
+	return &Document{
+		OpenRPC: OpenRPCVersion,
+		Methods: []Method{},
+		Reflector: &jsonschema.Reflector{
+			DefaultOptions: []func(rc *jsonschema.ReflectContext){
+				jsonschema.DefinitionsPrefix("#/components/schemas/"),
+			},
+		},
+	}
+}
+
+// Document is an OpenRPC document.
+type Document struct {
+	OpenRPC    string     `json:"openrpc"`
+	Info       Info       `json:"info"`
+	Methods    []Method   `json:"methods"`
+	Components Components `json:"components"`
+
+	// Reflector makes JSON schemas of params and results.
+	Reflector *jsonschema.Reflector `json:"-"`
+
+	mu sync.Mutex
+}
+
+// Info describes service.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Method describes JSON-RPC method.
+type Method struct {
+	Name           string              `json:"name"`
+	Summary        string              `json:"summary,omitempty"`
+	Description    string              `json:"description,omitempty"`
+	Tags           []Tag               `json:"tags,omitempty"`
+	ParamStructure string              `json:"paramStructure,omitempty"`
+	Params         []ContentDescriptor `json:"params"`
+	Result         *ContentDescriptor  `json:"result,omitempty"`
+	Errors         []Error             `json:"errors,omitempty"`
+	Deprecated     bool                `json:"deprecated,omitempty"`
+}
+
+// Tag describes group of methods.
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// ContentDescriptor describes param or result.
+type ContentDescriptor struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"`
+	Required    bool                    `json:"required,omitempty"`
+	Schema      jsonschema.SchemaOrBool `json:"schema"`
+}
+
+// Components keeps reusable schemas.
+type Components struct {
+	Schemas map[string]jsonschema.Schema `json:"schemas,omitempty"`
+}
+
+// MarshalJSON marshals document in a concurrency-safe way.
+func (d *Document) MarshalJSON() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	type doc Document
+
+	return json.Marshal((*doc)(d))
+}
+
+// ServeHTTP serves OpenRPC document.
+func (d *Document) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
+	writeJSON(rw, d)
+}
+
+func (d *Document) addMethod(u usecase.Interactor) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var (
+		hasName        usecase.HasName
+		hasTitle       usecase.HasTitle
+		hasDescription usecase.HasDescription
+		hasTags        usecase.HasTags
+		hasDeprecated  usecase.HasIsDeprecated
+		hasErrors      usecase.HasExpectedErrors
+		withInput      usecase.HasInputPort
+		withOutput     usecase.HasOutputPort
+	)
+
+	m := Method{ParamStructure: "by-name", Params: []ContentDescriptor{}}
+
+	if usecase.As(u, &hasName) {
+		m.Name = hasName.Name()
+	}
+
+	if usecase.As(u, &hasTitle) {
+		m.Summary = hasTitle.Title()
+	}
+
+	if usecase.As(u, &hasDescription) {
+		m.Description = hasDescription.Description()
+	}
+
+	if usecase.As(u, &hasTags) {
+		for _, t := range hasTags.Tags() {
+			m.Tags = append(m.Tags, Tag{Name: t})
+		}
+	}
+
+	if usecase.As(u, &hasDeprecated) {
+		m.Deprecated = hasDeprecated.IsDeprecated()
+	}
+
+	if usecase.As(u, &withInput) && withInput.InputPort() != nil {
+		params, err := d.params(withInput.InputPort())
+		if err != nil {
+			return err
+		}
+
+		m.Params = params
+	}
+
+	if usecase.As(u, &withOutput) && withOutput.OutputPort() != nil {
+		schema, err := d.reflect(withOutput.OutputPort())
+		if err != nil {
+			return err
+		}
+
+		m.Result = &ContentDescriptor{Name: "result", Schema: schema.ToSchemaOrBool()}
+	}
+
+	if usecase.As(u, &hasErrors) {
+		m.Errors = errorObjects(hasErrors.ExpectedErrors())
+	}
+
+	d.Methods = append(d.Methods, m)
+
+	return nil
+}
+
+// params makes content descriptors of top level properties of input.
+func (d *Document) params(input interface{}) ([]ContentDescriptor, error) {
+	schema, err := d.reflect(input)
+	if err != nil {
+		return nil, err
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	params := make([]ContentDescriptor, 0, len(names))
+
+	for _, name := range names {
+		p := ContentDescriptor{
+			Name:     name,
+			Required: required[name],
+			Schema:   schema.Properties[name],
+		}
+
+		if p.Schema.TypeObject != nil && p.Schema.TypeObject.Description != nil {
+			p.Description = *p.Schema.TypeObject.Description
+		}
+
+		params = append(params, p)
+	}
+
+	return params, nil
+}
+
+func (d *Document) reflect(v interface{}, options ...func(rc *jsonschema.ReflectContext)) (jsonschema.Schema, error) {
+	options = append(options, jsonschema.CollectDefinitions(func(name string, schema jsonschema.Schema) {
+		if d.Components.Schemas == nil {
+			d.Components.Schemas = make(map[string]jsonschema.Schema)
+		}
+
+		d.Components.Schemas[name] = schema
+	}))
+
+	return d.Reflector.Reflect(v, options...)
+}
+
+// errorObjects describes expected errors of use case.
+func errorObjects(expected []error) []Error {
+	res := make([]Error, 0, len(expected))
+	seen := make(map[int]bool, len(expected))
+
+	for _, err := range expected {
+		e := errorObject(err)
+
+		if seen[e.Code] {
+			continue
+		}
+
+		seen[e.Code] = true
+		e.Data = nil
+
+		res = append(res, *e)
+	}
+
+	return res
+}
@@ -0,0 +1,2 @@
+// Package lint checks routes and use cases for misconfiguration.
+package lint
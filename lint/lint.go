@@ -0,0 +1,487 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	oapi "github.com/swaggest/openapi-go"
+	"github.com/swaggest/refl"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/usecase"
+)
+
+// Severity describes importance of finding.
+type Severity string
+
+// Severity values.
+const (
+	// Error is a misconfiguration that leads to failures or lost data.
+	Error = Severity("error")
+
+	// Warning is a likely mistake or missing documentation.
+	Warning = Severity("warning")
+)
+
+// Rules.
+const (
+	RuleMappingUnknownField     = "mapping-unknown-field"
+	RulePathParamMissing        = "path-param-missing"
+	RulePathParamUndeclared     = "path-param-undeclared"
+	RuleBodyIgnored             = "body-ignored"
+	RuleOutputRendersNothing    = "output-renders-nothing"
+	RuleDuplicateOperationID    = "duplicate-operation-id"
+	RuleDuplicateUseCaseName    = "duplicate-use-case-name"
+	RuleUndocumentedErrorStatus = "undocumented-error-status"
+)
+
+// Finding describes a problem of a route.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Method   string   `json:"method,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Field    string   `json:"field,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// String returns human-readable finding.
+func (f Finding) String() string {
+	s := string(f.Severity) + " " + f.Rule + ": " + f.Method + " " + f.Pattern
+
+	if f.Field != "" {
+		s += " " + f.Field
+	}
+
+	return s + ": " + f.Message
+}
+
+// Findings is a list of problems.
+type Findings []Finding
+
+// Err returns error with all findings of Error severity, or nil if there are none.
+func (fs Findings) Err() error {
+	var msgs []string
+
+	for _, f := range fs {
+		if f.Severity == Error {
+			msgs = append(msgs, f.String())
+		}
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(msgs, "\n"))
+}
+
+// CheckRoutes checks use case handlers of chi router, for example web.Service or chirouter.Wrapper.
+//
+// Collector is optional, if set, operation IDs of OpenAPI document are checked for duplicates.
+func CheckRoutes(routes chi.Routes, c *openapi.Collector) (Findings, error) {
+	var (
+		res   Findings
+		names = map[string]string{}
+		ids   = map[string]string{}
+	)
+
+	operationIDs, err := loadOperationIDs(c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = chi.Walk(routes, func(method, route string, h http.Handler, _ ...func(http.Handler) http.Handler) error {
+		op := method + " " + route
+
+		var handler *nethttp.Handler
+
+		isHandler := nethttp.HandlerAs(h, &handler)
+		if isHandler {
+			res = append(res, CheckHandler(method, route, handler)...)
+		}
+
+		if id := operationIDs.find(method, route); id != "" {
+			if prev, found := ids[id]; found {
+				res = append(res, Finding{
+					Rule:     RuleDuplicateOperationID,
+					Severity: Error,
+					Method:   method,
+					Pattern:  route,
+					Message:  fmt.Sprintf("operation ID %q is already used by %s", id, prev),
+				})
+			} else {
+				ids[id] = op
+			}
+		}
+
+		if !isHandler {
+			return nil
+		}
+
+		var hasName usecase.HasName
+		if !usecase.As(handler.UseCase(), &hasName) || hasName.Name() == "" {
+			return nil
+		}
+
+		if prev, found := names[hasName.Name()]; found {
+			res = append(res, Finding{
+				Rule:     RuleDuplicateUseCaseName,
+				Severity: Warning,
+				Method:   method,
+				Pattern:  route,
+				Message:  fmt.Sprintf("use case name %q is already used by %s", hasName.Name(), prev),
+			})
+		} else {
+			names[hasName.Name()] = op
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+// operationIDs maps OpenAPI path and lowercase method to operation ID.
+type operationIDs map[string]map[string]string
+
+func loadOperationIDs(c *openapi.Collector) (operationIDs, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	data, err := c.CompactJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	res := make(operationIDs, len(doc.Paths))
+
+	for path, item := range doc.Paths {
+		ops := make(map[string]string, len(item))
+
+		for method, raw := range item {
+			var op struct {
+				ID string `json:"operationId"`
+			}
+
+			// Path item also contains non-operation properties that are skipped.
+			if err := json.Unmarshal(raw, &op); err == nil && op.ID != "" {
+				ops[method] = op.ID
+			}
+		}
+
+		res[path] = ops
+	}
+
+	return res, nil
+}
+
+func (o operationIDs) find(method, pattern string) string {
+	if o == nil {
+		return ""
+	}
+
+	_, path, _, err := oapi.SanitizeMethodPath(method, pattern)
+	if err != nil {
+		return ""
+	}
+
+	return o[path][strings.ToLower(method)]
+}
+
+// CheckHandler checks use case handler that is served with method and pattern.
+//
+// It can be called before handler is added to router to avoid panics during registration.
+func CheckHandler(method, pattern string, h *nethttp.Handler) Findings {
+	c := checker{method: strings.ToUpper(method), pattern: pattern, h: h}
+
+	var (
+		withInput  usecase.HasInputPort
+		withOutput usecase.HasOutputPort
+	)
+
+	u := h.UseCase()
+
+	if usecase.As(u, &withInput) && withInput.InputPort() != nil {
+		c.input(withInput.InputPort())
+	}
+
+	if usecase.As(u, &withOutput) && withOutput.OutputPort() != nil {
+		c.output(withOutput.OutputPort())
+	}
+
+	return c.findings
+}
+
+type checker struct {
+	method   string
+	pattern  string
+	h        *nethttp.Handler
+	findings Findings
+}
+
+func (c *checker) add(rule string, severity Severity, field, format string, args ...interface{}) {
+	c.findings = append(c.findings, Finding{
+		Rule:     rule,
+		Severity: severity,
+		Method:   c.method,
+		Pattern:  c.pattern,
+		Field:    field,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+var paramTags = []string{
+	string(rest.ParamInPath), string(rest.ParamInQuery), string(rest.ParamInHeader),
+	string(rest.ParamInCookie), string(rest.ParamInFormData), "file",
+}
+
+func (c *checker) input(input interface{}) {
+	v := reflect.ValueOf(input)
+
+	fields := map[string]bool{}
+
+	refl.WalkTaggedFields(v, func(_ reflect.Value, sf reflect.StructField, _ string) {
+		fields[sf.Name] = true
+	}, "")
+
+	mapping := c.h.ReqMapping
+
+	ins := make([]string, 0, len(mapping))
+	for in := range mapping {
+		ins = append(ins, string(in))
+	}
+
+	sort.Strings(ins)
+
+	for _, in := range ins {
+		for _, field := range sortedKeys(mapping[rest.ParamIn(in)]) {
+			if !fields[field] {
+				c.add(RuleMappingUnknownField, Error, field, "%s mapping refers to non existent field", in)
+			}
+		}
+	}
+
+	// Path parameters of input.
+	declared := map[string]string{}
+
+	if m, ok := mapping[rest.ParamInPath]; ok {
+		for field, name := range m {
+			declared[name] = field
+		}
+	} else {
+		refl.WalkTaggedFields(v, func(_ reflect.Value, sf reflect.StructField, tag string) {
+			declared[tag] = sf.Name
+		}, string(rest.ParamInPath))
+	}
+
+	inPattern := map[string]bool{}
+	for _, p := range patternParams(c.pattern) {
+		inPattern[p] = true
+
+		if _, ok := declared[p]; !ok {
+			c.add(RulePathParamUndeclared, Warning, "", "path parameter %q is not declared in input", p)
+		}
+	}
+
+	for _, name := range sortedKeys(declared) {
+		if !inPattern[name] {
+			c.add(RulePathParamMissing, Error, declared[name], "path parameter %q is missing in pattern", name)
+		}
+	}
+
+	hasParams := len(mapping) > 0 || len(declared) > 0
+
+	for _, tag := range paramTags {
+		if refl.HasTaggedFields(input, tag) {
+			hasParams = true
+		}
+	}
+
+	bodyFields := jsonBodyFields(v)
+
+	_, forceRequestBody := input.(oapi.RequestBodyEnforcer)
+	if c.method != http.MethodPost && c.method != http.MethodPut && c.method != http.MethodPatch && !forceRequestBody {
+		for _, f := range bodyFields {
+			c.add(RuleBodyIgnored, Error, f, "json body field is not decoded for %s request", c.method)
+		}
+	} else if len(bodyFields) > 0 {
+		hasParams = true
+	}
+
+	// Only use cases that declare expected errors are expected to document all of them.
+	if hasParams && c.declaresErrors() && !c.documentsBadRequest() {
+		c.add(RuleUndocumentedErrorStatus, Warning, "",
+			"invalid request responds with %d %s, but it is not an expected error",
+			http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+	}
+}
+
+func (c *checker) declaresErrors() bool {
+	var hasExpectedErrors usecase.HasExpectedErrors
+
+	return usecase.As(c.h.UseCase(), &hasExpectedErrors) && len(hasExpectedErrors.ExpectedErrors()) > 0
+}
+
+func (c *checker) documentsBadRequest() bool {
+	var hasExpectedErrors usecase.HasExpectedErrors
+	if !usecase.As(c.h.UseCase(), &hasExpectedErrors) {
+		return false
+	}
+
+	for _, e := range hasExpectedErrors.ExpectedErrors() {
+		var code int
+
+		if c.h.MakeErrResp != nil {
+			code, _ = c.h.MakeErrResp(context.Background(), e)
+		} else {
+			code, _ = rest.Err(e)
+		}
+
+		if code == http.StatusBadRequest {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *checker) output(output interface{}) {
+	t := reflect.TypeOf(output)
+
+	if _, ok := output.(usecase.OutputWithWriter); ok {
+		return
+	}
+
+	if t.Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()) {
+		return
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || t.NumField() == 0 {
+		return
+	}
+
+	if !renders(t) {
+		c.add(RuleOutputRendersNothing, Warning, "",
+			"output %s has no exported fields for response body or headers", t.String())
+	}
+}
+
+// renders checks if struct has fields that are encoded in response body or headers.
+func renders(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		if _, ok := sf.Tag.Lookup(string(rest.ParamInHeader)); ok {
+			return true
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if sf.Anonymous && ft.Kind() == reflect.Struct {
+			if renders(ft) {
+				return true
+			}
+
+			continue
+		}
+
+		if sf.PkgPath != "" || sf.Tag.Get("json") == "-" {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// jsonBodyFields returns names of fields with `json` tag that are not request parameters.
+func jsonBodyFields(v reflect.Value) []string {
+	var res []string
+
+	refl.WalkTaggedFields(v, func(_ reflect.Value, sf reflect.StructField, tag string) {
+		if tag == "-" {
+			return
+		}
+
+		for _, t := range paramTags {
+			if _, ok := sf.Tag.Lookup(t); ok {
+				return
+			}
+		}
+
+		res = append(res, sf.Name)
+	}, "json")
+
+	return res
+}
+
+// patternParams returns names of parameters of chi route pattern.
+func patternParams(pattern string) []string {
+	var (
+		res   []string
+		depth int
+		start int
+	)
+
+	for i, r := range pattern {
+		switch r {
+		case '{':
+			if depth == 0 {
+				start = i + 1
+			}
+
+			depth++
+		case '}':
+			depth--
+
+			if depth == 0 {
+				name := pattern[start:i]
+				if j := strings.Index(name, ":"); j >= 0 {
+					name = name[:j]
+				}
+
+				res = append(res, name)
+			}
+		}
+	}
+
+	return res
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,133 @@
+package lint_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/lint"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+func TestService_Check(t *testing.T) {
+	s := web.NewService(nil)
+
+	type getUser struct {
+		ID     int    `path:"id"`
+		Locale string `query:"locale"`
+		Filter string `json:"filter"`
+	}
+
+	type user struct {
+		name string
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, _ getUser, _ *user) error { return nil })
+	u.SetName("getUser")
+	u.SetExpectedErrors(status.NotFound)
+
+	s.Get("/users/{id}", u)
+
+	type listTags struct {
+		Limit int `query:"limit"`
+	}
+
+	// Use case without expected errors is not checked for undocumented errors,
+	// operation ID set with annotation duplicates ID of another operation.
+	l := usecase.NewInteractor(func(_ context.Context, _ listTags, _ *[]string) error { return nil })
+
+	s.Get("/tags", l, nethttp.AnnotateOpenAPIOperation(func(oc openapi.OperationContext) error {
+		oc.SetID("getUser")
+
+		return nil
+	}))
+
+	type createUser struct {
+		Name string `json:"name"`
+	}
+
+	c := usecase.NewInteractor(func(_ context.Context, _ createUser, _ *struct{}) error { return nil })
+	c.SetName("getUser")
+	c.SetExpectedErrors(status.InvalidArgument)
+
+	s.Post("/users", c)
+
+	findings, err := s.Check()
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `[
+	  {
+		"rule":"body-ignored","severity":"error","method":"GET","pattern":"/users/{id}","field":"Filter",
+		"message":"json body field is not decoded for GET request"
+	  },
+	  {
+		"rule":"undocumented-error-status","severity":"warning","method":"GET","pattern":"/users/{id}",
+		"message":"invalid request responds with 400 Bad Request, but it is not an expected error"
+	  },
+	  {
+		"rule":"output-renders-nothing","severity":"warning","method":"GET","pattern":"/users/{id}",
+		"message":"output lint_test.user has no exported fields for response body or headers"
+	  },
+	  {
+		"rule":"duplicate-operation-id","severity":"error","method":"GET","pattern":"/users/{id}",
+		"message":"operation ID \"getUser\" is already used by GET /tags"
+	  },
+	  {
+		"rule":"duplicate-use-case-name","severity":"warning","method":"GET","pattern":"/users/{id}",
+		"message":"use case name \"getUser\" is already used by POST /users"
+	  }
+	]`, findings)
+
+	assert.EqualError(t, findings.Err(), "error body-ignored: GET /users/{id} Filter: "+
+		"json body field is not decoded for GET request\n"+
+		"error duplicate-operation-id: GET /users/{id}: operation ID \"getUser\" is already used by GET /tags")
+}
+
+func TestCheckHandler(t *testing.T) {
+	type req struct {
+		ID   int    `path:"id"`
+		Name string `query:"name"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, _ req, _ *struct{}) error { return nil })
+	u.SetExpectedErrors(rest.HTTPCodeAsError(http.StatusBadRequest))
+
+	h := nethttp.NewHandler(u, nethttp.RequestMapping(new(struct {
+		ID   int    `path:"user_id"`
+		Name string `query:"name"`
+		Sort string `query:"sort"`
+	})))
+
+	findings := lint.CheckHandler(http.MethodGet, "/users/{userID:[0-9]+}", h)
+
+	assertjson.EqMarshal(t, `[
+	  {
+		"rule":"mapping-unknown-field","severity":"error","method":"GET","pattern":"/users/{userID:[0-9]+}",
+		"field":"Sort","message":"query mapping refers to non existent field"
+	  },
+	  {
+		"rule":"path-param-undeclared","severity":"warning","method":"GET","pattern":"/users/{userID:[0-9]+}",
+		"message":"path parameter \"userID\" is not declared in input"
+	  },
+	  {
+		"rule":"path-param-missing","severity":"error","method":"GET","pattern":"/users/{userID:[0-9]+}",
+		"field":"ID","message":"path parameter \"user_id\" is missing in pattern"
+	  }
+	]`, findings)
+
+	assert.Empty(t, lint.CheckHandler(http.MethodGet, "/users/{id}", nethttp.NewHandler(u)))
+	assert.Empty(t, lint.CheckHandler(http.MethodGet, "/users/{id}", nethttp.NewHandler(
+		usecase.NewInteractor(func(_ context.Context, _ req, _ *struct{}) error { return nil }))))
+	assert.Empty(t, lint.CheckHandler(http.MethodGet, "/users/{user_id}", nethttp.NewHandler(u,
+		nethttp.RequestMapping(new(struct {
+			ID int `path:"user_id"`
+		})))))
+}
@@ -0,0 +1,2 @@
+// Package listquery provides filtering, sorting and sparse fieldsets query parameters for list endpoints.
+package listquery
@@ -0,0 +1,158 @@
+package listquery
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+)
+
+// Field tags to declare capabilities of item fields, field name is taken from `json` tag.
+const (
+	FilterableTag = "filterable"
+	SortableTag   = "sortable"
+)
+
+// Params is an embeddable use case input with filtering, sorting and sparse fieldsets of items of type T.
+//
+// Example: `filter[status]=done&sort=-createdAt,title&fields=id,title`.
+//
+// Fields of T are declared as filterable or sortable with field tags, e.g.
+//
+//	Status string `json:"status" filterable:"true" sortable:"true"`
+//
+// Allowed field names are exposed as enums in request schema, so that unknown fields are rejected
+// by request validation.
+type Params[T any] struct {
+	Filter Filter[T] `query:"filter" description:"Filter by field values, e.g. filter[status]=done, comma-separated values match any."`
+	Sort   Sort[T]   `query:"sort" collectionFormat:"csv" description:"Comma-separated list of sort fields, prefix with - for descending order."`
+	Fields Fields[T] `query:"fields" collectionFormat:"csv" description:"Comma-separated list of fields to include in response."`
+}
+
+// Query returns parsed list query.
+func (p Params[T]) Query() Query {
+	q := Query{}
+
+	names := make([]string, 0, len(p.Filter))
+	for name := range p.Filter {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		q.Filters = append(q.Filters, Condition{
+			Field:  name,
+			Values: strings.Split(p.Filter[name], ","),
+		})
+	}
+
+	for _, s := range p.Sort {
+		if strings.HasPrefix(s, "-") {
+			q.Sort = append(q.Sort, SortKey{Field: s[1:], Desc: true})
+		} else {
+			q.Sort = append(q.Sort, SortKey{Field: s})
+		}
+	}
+
+	q.Fields = append(q.Fields, p.Fields...)
+
+	return q
+}
+
+// SelectedOutputFields implements rest.OutputFieldsSelector to prune response with sparse fieldset.
+func (p Params[T]) SelectedOutputFields() []string {
+	return p.Fields
+}
+
+// Query is a parsed list query.
+type Query struct {
+	// Filters are ordered by field name.
+	Filters []Condition
+
+	// Sort keys are in order of priority.
+	Sort []SortKey
+
+	// Fields is a sparse fieldset, empty for all fields.
+	Fields []string
+}
+
+// Condition requires field to have one of values.
+type Condition struct {
+	Field  string
+	Values []string
+}
+
+// SortKey defines sort order by field.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// Filter is a map of filtered field names to values.
+type Filter[T any] map[string]string
+
+// PrepareJSONSchema declares filterable fields.
+func (Filter[T]) PrepareJSONSchema(s *jsonschema.Schema) error {
+	s.AdditionalProperties = (&jsonschema.SchemaOrBool{}).WithTypeBoolean(false)
+	s.Properties = make(map[string]jsonschema.SchemaOrBool)
+
+	for _, name := range taggedFields[T](FilterableTag) {
+		s.Properties[name] = jsonschema.String.ToSchemaOrBool()
+	}
+
+	return nil
+}
+
+// Sort is a list of sort fields, descending order is indicated with "-" prefix.
+type Sort[T any] []string
+
+// PrepareJSONSchema declares sortable fields.
+func (Sort[T]) PrepareJSONSchema(s *jsonschema.Schema) error {
+	var enum []interface{}
+
+	for _, name := range taggedFields[T](SortableTag) {
+		enum = append(enum, name, "-"+name)
+	}
+
+	s.ItemsEns().SchemaOrBoolEns().TypeObjectEns().WithType(jsonschema.String.Type()).WithEnum(enum...)
+
+	return nil
+}
+
+// Fields is a sparse fieldset.
+type Fields[T any] []string
+
+// PrepareJSONSchema declares available fields.
+func (Fields[T]) PrepareJSONSchema(s *jsonschema.Schema) error {
+	var enum []interface{}
+
+	for _, name := range taggedFields[T]("") {
+		enum = append(enum, name)
+	}
+
+	s.ItemsEns().SchemaOrBoolEns().TypeObjectEns().WithType(jsonschema.String.Type()).WithEnum(enum...)
+
+	return nil
+}
+
+// taggedFields returns JSON names of fields of T that have a truthy tag, empty tag selects all fields.
+func taggedFields[T any](tag string) []string {
+	var names []string
+
+	refl.WalkTaggedFields(reflect.ValueOf(new(T)), func(_ reflect.Value, sf reflect.StructField, name string) {
+		if name == "-" {
+			return
+		}
+
+		if tag != "" && sf.Tag.Get(tag) != "true" {
+			return
+		}
+
+		names = append(names, strings.Split(name, ",")[0])
+	}, "json")
+
+	return names
+}
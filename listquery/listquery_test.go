@@ -0,0 +1,153 @@
+package listquery_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/listquery"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+)
+
+type task struct {
+	ID        int    `json:"id"`
+	Title     string `json:"title" sortable:"true"`
+	Status    string `json:"status" filterable:"true"`
+	CreatedAt string `json:"createdAt" filterable:"true" sortable:"true"`
+}
+
+func TestParams(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+
+	var q listquery.Query
+
+	type listInput struct {
+		listquery.Params[task]
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, in listInput, out *[]task) error {
+		q = in.Query()
+
+		*out = []task{{ID: 1, Title: "Foo", Status: "done", CreatedAt: "2024-01-01"}}
+
+		return nil
+	})
+	u.SetName("listTasks")
+
+	s.Get("/tasks", u)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/tasks?filter[status]=done,open&filter[createdAt]=2024-01-01&sort=-createdAt,title&fields=id,title", nil)
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code, rw.Body.String())
+	assert.Equal(t, `[{"id":1,"title":"Foo"}]`+"\n", rw.Body.String())
+	assert.Equal(t, listquery.Query{
+		Filters: []listquery.Condition{
+			{Field: "createdAt", Values: []string{"2024-01-01"}},
+			{Field: "status", Values: []string{"done", "open"}},
+		},
+		Sort: []listquery.SortKey{
+			{Field: "createdAt", Desc: true},
+			{Field: "title"},
+		},
+		Fields: []string{"id", "title"},
+	}, q)
+
+	for _, u := range []string{"/tasks?filter[title]=Foo", "/tasks?sort=status", "/tasks?fields=id,secret"} {
+		req = httptest.NewRequest(http.MethodGet, u, nil)
+		rw = httptest.NewRecorder()
+		s.ServeHTTP(rw, req)
+
+		assert.Equal(t, http.StatusBadRequest, rw.Code, u)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, `[{"id":1,"title":"Foo","status":"done","createdAt":"2024-01-01"}]`+"\n", rw.Body.String())
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+	    "/tasks":{
+	      "get":{
+	        "summary":"Test Params","operationId":"listTasks",
+	        "parameters":[
+	          {
+	            "name":"filter","in":"query",
+	            "description":"Filter by field values, e.g. filter[status]=done, comma-separated values match any.",
+	            "style":"deepObject","explode":true,
+	            "schema":{
+	              "$ref":"#/components/schemas/ListqueryFilterGithubComSwaggestRestListqueryTestTask"
+	            }
+	          },
+	          {
+	            "name":"sort","in":"query",
+	            "description":"Comma-separated list of sort fields, prefix with - for descending order.",
+	            "style":"form","explode":false,
+	            "schema":{
+	              "$ref":"#/components/schemas/ListquerySortGithubComSwaggestRestListqueryTestTask"
+	            }
+	          },
+	          {
+	            "name":"fields","in":"query",
+	            "description":"Comma-separated list of fields to include in response.",
+	            "style":"form","explode":false,
+	            "schema":{
+	              "$ref":"#/components/schemas/ListqueryFieldsGithubComSwaggestRestListqueryTestTask"
+	            }
+	          }
+	        ],
+	        "responses":{
+	          "200":{
+	            "description":"OK",
+	            "content":{
+	              "application/json":{
+	                "schema":{
+	                  "type":"array",
+	                  "items":{"$ref":"#/components/schemas/ListqueryTestTask"}
+	                }
+	              }
+	            }
+	          }
+	        }
+	      }
+	    }
+	  },
+	  "components":{
+	    "schemas":{
+	      "ListqueryFieldsGithubComSwaggestRestListqueryTestTask":{
+	        "type":"array",
+	        "items":{"enum":["id","title","status","createdAt"],"type":"string"},
+	        "nullable":true
+	      },
+	      "ListqueryFilterGithubComSwaggestRestListqueryTestTask":{
+	        "type":"object",
+	        "properties":{"createdAt":{"type":"string"},"status":{"type":"string"}},
+	        "additionalProperties":false
+	      },
+	      "ListquerySortGithubComSwaggestRestListqueryTestTask":{
+	        "type":"array",
+	        "items":{"enum":["title","-title","createdAt","-createdAt"],"type":"string"},
+	        "nullable":true
+	      },
+	      "ListqueryTestTask":{
+	        "type":"object",
+	        "properties":{
+	          "createdAt":{"type":"string"},"id":{"type":"integer"},
+	          "status":{"type":"string"},"title":{"type":"string"}
+	        }
+	      }
+	    }
+	  }
+	}`, s.OpenAPISchema())
+}
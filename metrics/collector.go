@@ -0,0 +1,300 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/usecase"
+)
+
+// Error kinds.
+const (
+	ErrorNone       = ""
+	ErrorDecode     = "decode"
+	ErrorValidation = "validation"
+	ErrorInteractor = "interactor"
+)
+
+// DefaultLatencyBuckets are upper bounds of latency histograms in seconds.
+var DefaultLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DefaultSizeBuckets are upper bounds of size histograms in bytes.
+var DefaultSizeBuckets = []float64{100, 1000, 10000, 100000, 1e6, 1e7}
+
+// NewCollector creates metrics collector.
+func NewCollector(options ...func(c *Collector)) *Collector {
+	c := Collector{
+		Namespace:      "rest",
+		LatencyBuckets: DefaultLatencyBuckets,
+		SizeBuckets:    DefaultSizeBuckets,
+	}
+
+	for _, o := range options {
+		o(&c)
+	}
+
+	return &c
+}
+
+// Collector collects metrics of use case handlers and serves them in Prometheus text exposition format.
+//
+// Collector.Middleware is to be added with chirouter.Wrapper.Wrap, it instruments handlers that have
+// route information, so that metrics are labeled with operation ID and route pattern and cardinality
+// stays bounded. Collector itself is an http.Handler that can be mounted on a service.
+//
+// Please use NewCollector to create instance.
+type Collector struct {
+	// Namespace is a prefix of metric names, default "rest".
+	Namespace string
+
+	// LatencyBuckets are upper bounds of latency histograms in seconds, default DefaultLatencyBuckets.
+	LatencyBuckets []float64
+
+	// SizeBuckets are upper bounds of request and response size histograms in bytes, default DefaultSizeBuckets.
+	SizeBuckets []float64
+
+	mu         sync.Mutex
+	operations []*operation
+}
+
+type operation struct {
+	id      string
+	method  string
+	pattern string
+
+	mu             sync.Mutex
+	requests       map[requestKey]uint64
+	requestLatency *histogram
+	phaseLatency   map[nethttp.Phase]*histogram
+	requestSize    *histogram
+	responseSize   *histogram
+}
+
+type requestKey struct {
+	status    int
+	errorKind string
+}
+
+type requestState struct {
+	errorKind string
+}
+
+type requestStateCtxKey struct{}
+
+// Middleware instruments nethttp.Handler with route information.
+func (c *Collector) Middleware(h http.Handler) http.Handler {
+	if nethttp.IsWrapperChecker(h) {
+		return h
+	}
+
+	var (
+		withRoute rest.HandlerWithRoute
+		handler   *nethttp.Handler
+	)
+
+	if !nethttp.HandlerAs(h, &withRoute) || !nethttp.HandlerAs(h, &handler) {
+		return h
+	}
+
+	op := c.operation(handler.UseCase(), withRoute.RouteMethod(), withRoute.RoutePattern())
+
+	handler.PhaseHooks = append(handler.PhaseHooks, op.phaseHook)
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		st := &requestState{}
+		cw := &nethttp.StatusWriter{ResponseWriter: rw}
+
+		// Body is counted when content length is unknown.
+		var body *countingBody
+		if r.ContentLength < 0 && r.Body != nil && r.Body != http.NoBody {
+			body = &countingBody{ReadCloser: r.Body}
+			r.Body = body
+		}
+
+		h.ServeHTTP(cw, r.WithContext(context.WithValue(r.Context(), requestStateCtxKey{}, st)))
+
+		reqSize := int(r.ContentLength)
+		if reqSize < 0 && body != nil {
+			reqSize = body.n
+		}
+
+		op.observeRequest(requestKey{status: cw.StatusCode(), errorKind: st.errorKind}, time.Since(start), reqSize, cw.Written)
+	})
+}
+
+func (c *Collector) operation(u usecase.Interactor, method, pattern string) *operation {
+	op := &operation{
+		method:       method,
+		pattern:      pattern,
+		requests:     make(map[requestKey]uint64),
+		phaseLatency: make(map[nethttp.Phase]*histogram),
+	}
+
+	var hasName usecase.HasName
+	if usecase.As(u, &hasName) {
+		op.id = hasName.Name()
+	}
+
+	op.requestLatency = newHistogram(c.LatencyBuckets)
+	op.requestSize = newHistogram(c.SizeBuckets)
+	op.responseSize = newHistogram(c.SizeBuckets)
+
+	for _, p := range phases {
+		op.phaseLatency[p] = newHistogram(c.LatencyBuckets)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.operations = append(c.operations, op)
+
+	return op
+}
+
+var phases = []nethttp.Phase{nethttp.PhaseDecode, nethttp.PhaseValidate, nethttp.PhaseInteract, nethttp.PhaseEncode}
+
+func (op *operation) phaseHook(ctx context.Context, phase nethttp.Phase) (context.Context, func(err error)) {
+	start := time.Now()
+
+	return ctx, func(err error) {
+		elapsed := time.Since(start)
+
+		if err != nil {
+			if st, ok := ctx.Value(requestStateCtxKey{}).(*requestState); ok && st.errorKind == ErrorNone {
+				switch phase {
+				case nethttp.PhaseValidate:
+					st.errorKind = ErrorValidation
+				case nethttp.PhaseDecode:
+					st.errorKind = ErrorDecode
+				case nethttp.PhaseInteract:
+					st.errorKind = ErrorInteractor
+				}
+			}
+		}
+
+		op.mu.Lock()
+		defer op.mu.Unlock()
+
+		if h, ok := op.phaseLatency[phase]; ok {
+			h.observe(elapsed.Seconds())
+		}
+	}
+}
+
+func (op *operation) observeRequest(k requestKey, elapsed time.Duration, reqSize, respSize int) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	op.requests[k]++
+	op.requestLatency.observe(elapsed.Seconds())
+	op.requestSize.observe(float64(reqSize))
+	op.responseSize.observe(float64(respSize))
+}
+
+// ServeHTTP writes collected metrics in Prometheus text exposition format.
+func (c *Collector) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	_, _ = c.WriteTo(rw)
+}
+
+// WriteTo writes collected metrics in Prometheus text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	ops := append([]*operation(nil), c.operations...)
+	c.mu.Unlock()
+
+	sort.SliceStable(ops, func(i, j int) bool {
+		if ops[i].pattern != ops[j].pattern {
+			return ops[i].pattern < ops[j].pattern
+		}
+
+		return ops[i].method < ops[j].method
+	})
+
+	e := exposition{w: w}
+	ns := c.Namespace + "_"
+
+	e.header(ns+"requests_total", "counter", "Number of served requests.")
+
+	for _, op := range ops {
+		op.mu.Lock()
+
+		keys := make([]requestKey, 0, len(op.requests))
+		for k := range op.requests {
+			keys = append(keys, k)
+		}
+
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].status != keys[j].status {
+				return keys[i].status < keys[j].status
+			}
+
+			return keys[i].errorKind < keys[j].errorKind
+		})
+
+		for _, k := range keys {
+			e.sample(ns+"requests_total", op.labels(
+				"status", strconv.Itoa(k.status),
+				"error", k.errorKind,
+			), float64(op.requests[k]))
+		}
+
+		op.mu.Unlock()
+	}
+
+	e.histograms(ns+"request_duration_seconds", "Duration of requests.", ops,
+		func(op *operation) []labeledHistogram {
+			return []labeledHistogram{{labels: op.labels(), h: op.requestLatency}}
+		})
+
+	e.histograms(ns+"phase_duration_seconds", "Duration of request processing phases.", ops,
+		func(op *operation) []labeledHistogram {
+			res := make([]labeledHistogram, 0, len(phases))
+
+			for _, p := range phases {
+				if op.phaseLatency[p].count > 0 {
+					res = append(res, labeledHistogram{labels: op.labels("phase", string(p)), h: op.phaseLatency[p]})
+				}
+			}
+
+			return res
+		})
+
+	e.histograms(ns+"request_size_bytes", "Size of request bodies.", ops,
+		func(op *operation) []labeledHistogram {
+			return []labeledHistogram{{labels: op.labels(), h: op.requestSize}}
+		})
+
+	e.histograms(ns+"response_size_bytes", "Size of response bodies.", ops,
+		func(op *operation) []labeledHistogram {
+			return []labeledHistogram{{labels: op.labels(), h: op.responseSize}}
+		})
+
+	return e.n, e.err
+}
+
+// labels returns operation labels with additional name-value pairs.
+func (op *operation) labels(nameValues ...string) []string {
+	return append([]string{"operation", op.id, "method", op.method, "route", op.pattern}, nameValues...)
+}
+
+type countingBody struct {
+	io.ReadCloser
+	n int
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += n
+
+	return n, err
+}
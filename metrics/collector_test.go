@@ -0,0 +1,91 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/metrics"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+)
+
+func TestCollector(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector(), func(s *web.Service) {
+		s.Metrics = metrics.NewCollector(func(c *metrics.Collector) {
+			c.LatencyBuckets = []float64{10}
+			c.SizeBuckets = []float64{10, 100}
+		})
+	})
+
+	type putInput struct {
+		ID   int    `path:"id" minimum:"1"`
+		Name string `json:"name"`
+	}
+
+	type putOutput struct {
+		ID int `json:"id"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, in putInput, out *putOutput) error {
+		if in.ID == 13 {
+			return errors.New("unlucky")
+		}
+
+		out.ID = in.ID
+
+		return nil
+	})
+	u.SetName("putItem")
+
+	s.Put("/items/{id}", u)
+	s.Method(http.MethodGet, "/metrics", s.Metrics)
+
+	for _, r := range []struct{ path, body string }{
+		{"/items/1", `{"name":"foo"}`},
+		{"/items/0", `{"name":"foo"}`},
+		{"/items/2", `{"name":`},
+		{"/items/13", `{}`},
+	} {
+		req := httptest.NewRequest(http.MethodPut, r.path, strings.NewReader(r.body))
+		rw := httptest.NewRecorder()
+		s.ServeHTTP(rw, req)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rw.Header().Get("Content-Type"))
+
+	body := rw.Body.String()
+	labels := `operation="putItem",method="PUT",route="/items/{id}"`
+
+	for _, line := range []string{
+		"# TYPE rest_requests_total counter",
+		`rest_requests_total{` + labels + `,status="200",error=""} 1`,
+		`rest_requests_total{` + labels + `,status="400",error="decode"} 1`,
+		`rest_requests_total{` + labels + `,status="400",error="validation"} 1`,
+		`rest_requests_total{` + labels + `,status="500",error="interactor"} 1`,
+		"# TYPE rest_request_duration_seconds histogram",
+		`rest_request_duration_seconds_bucket{` + labels + `,le="10"} 4`,
+		`rest_request_duration_seconds_bucket{` + labels + `,le="+Inf"} 4`,
+		`rest_request_duration_seconds_count{` + labels + `} 4`,
+		`rest_phase_duration_seconds_count{` + labels + `,phase="decode"} 4`,
+		`rest_phase_duration_seconds_count{` + labels + `,phase="interact"} 2`,
+		`rest_phase_duration_seconds_count{` + labels + `,phase="encode"} 4`,
+		`rest_request_size_bytes_bucket{` + labels + `,le="10"} 2`,
+		`rest_request_size_bytes_sum{` + labels + `} 38`,
+		`rest_response_size_bytes_bucket{` + labels + `,le="10"} 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+
+	assert.NotContains(t, body, "/metrics")
+}
@@ -0,0 +1,2 @@
+// Package metrics provides request metrics of use case handlers in Prometheus text exposition format.
+package metrics
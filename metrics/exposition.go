@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// histogram counts observations in cumulative buckets.
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, b := range h.bounds {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+
+	h.count++
+	h.sum += v
+}
+
+type labeledHistogram struct {
+	labels []string
+	h      *histogram
+}
+
+// exposition writes metrics in Prometheus text format.
+type exposition struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (e *exposition) write(s string) {
+	if e.err != nil {
+		return
+	}
+
+	n, err := io.WriteString(e.w, s)
+	e.n += int64(n)
+	e.err = err
+}
+
+func (e *exposition) header(name, typ, help string) {
+	e.write("# HELP " + name + " " + help + "\n# TYPE " + name + " " + typ + "\n")
+}
+
+func (e *exposition) sample(name string, labels []string, value float64) {
+	var sb strings.Builder
+
+	sb.WriteString(name)
+
+	if len(labels) > 0 {
+		sb.WriteString("{")
+
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+
+			sb.WriteString(labels[i])
+			sb.WriteString(`="`)
+			sb.WriteString(labelEscaper.Replace(labels[i+1]))
+			sb.WriteString(`"`)
+		}
+
+		sb.WriteString("}")
+	}
+
+	sb.WriteString(" ")
+	sb.WriteString(formatFloat(value))
+	sb.WriteString("\n")
+
+	e.write(sb.String())
+}
+
+func (e *exposition) histograms(name, help string, ops []*operation, get func(op *operation) []labeledHistogram) {
+	e.header(name, "histogram", help)
+
+	for _, op := range ops {
+		op.mu.Lock()
+
+		for _, lh := range get(op) {
+			for i, b := range lh.h.bounds {
+				e.sample(name+"_bucket", append(lh.labels[:len(lh.labels):len(lh.labels)], "le", formatFloat(b)),
+					float64(lh.h.counts[i]))
+			}
+
+			e.sample(name+"_bucket", append(lh.labels[:len(lh.labels):len(lh.labels)], "le", "+Inf"),
+				float64(lh.h.count))
+			e.sample(name+"_sum", lh.labels, lh.h.sum)
+			e.sample(name+"_count", lh.labels, float64(lh.h.count))
+		}
+
+		op.mu.Unlock()
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
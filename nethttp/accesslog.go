@@ -0,0 +1,280 @@
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/swaggest/rest"
+	"github.com/swaggest/usecase"
+)
+
+// AccessLog describes a request served by use case handler.
+type AccessLog struct {
+	Method      string
+	Pattern     string
+	OperationID string
+	Status      int
+	Latency     time.Duration
+
+	// Input is decoded use case input, it is nil if use case has no input.
+	Input interface{}
+
+	// Err is an error that was served with error response.
+	Err error
+}
+
+// Redacted is a placeholder of redacted values in AccessLog.InputSummary.
+const Redacted = "[REDACTED]"
+
+// ErrorChain returns messages of error and its unwrapped causes (for example, original error of rest.ErrResponse).
+func (l AccessLog) ErrorChain() []string {
+	var chain []string
+
+	for err := l.Err; err != nil; err = errors.Unwrap(err) {
+		msg := err.Error()
+
+		if len(chain) == 0 || chain[len(chain)-1] != msg {
+			chain = append(chain, msg)
+		}
+	}
+
+	return chain
+}
+
+// InputSummary returns values of input fields by their names in request or body.
+//
+// Values of fields tagged with `redact:"true"` are replaced with Redacted, including fields of nested structures,
+// pointers, slices and maps. Values that have no redacted fields are returned as is.
+func (l AccessLog) InputSummary() map[string]interface{} {
+	v := reflect.ValueOf(l.Input)
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	summary := make(map[string]interface{})
+	summarizeStruct(v, summary)
+
+	return summary
+}
+
+var inputNameTags = []string{"json", "path", "query", "header", "cookie", "formData", "form", "contentType"}
+
+func summarizeStruct(v reflect.Value, summary map[string]interface{}) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+
+		if f.Anonymous {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+
+			if fv.Kind() == reflect.Struct {
+				summarizeStruct(fv, summary)
+			}
+
+			continue
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := f.Name
+
+		for _, tag := range inputNameTags {
+			if n := strings.Split(f.Tag.Get(tag), ",")[0]; n != "" {
+				name = n
+
+				break
+			}
+		}
+
+		if name == "-" {
+			continue
+		}
+
+		if f.Tag.Get("redact") == "true" {
+			summary[name] = Redacted
+
+			continue
+		}
+
+		summary[name] = summarizeValue(fv)
+	}
+}
+
+// summarizeValue returns value with redacted fields replaced, values without redacted fields are not changed.
+func summarizeValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if !hasRedacted(v.Type(), map[reflect.Type]bool{}) {
+		return v.Interface()
+	}
+
+	switch v.Kind() { //nolint:exhaustive // Other kinds have no fields.
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		return summarizeValue(v.Elem())
+	case reflect.Struct:
+		summary := make(map[string]interface{})
+		summarizeStruct(v, summary)
+
+		return summary
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = summarizeValue(v.Index(i))
+		}
+
+		return items
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		items := make(map[string]interface{}, v.Len())
+
+		iter := v.MapRange()
+		for iter.Next() {
+			items[fmt.Sprint(iter.Key().Interface())] = summarizeValue(iter.Value())
+		}
+
+		return items
+	}
+
+	return v.Interface()
+}
+
+// hasRedacted checks if type may contain fields tagged with `redact:"true"`.
+//
+// Interface types are checked by dynamic values.
+func hasRedacted(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+
+	visited[t] = true
+
+	switch t.Kind() { //nolint:exhaustive // Other kinds have no fields.
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return hasRedacted(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+
+			if f.PkgPath != "" && !f.Anonymous {
+				continue
+			}
+
+			if f.Tag.Get("redact") == "true" || hasRedacted(f.Type, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type accessLogCtxKey struct{}
+
+type accessLogState struct {
+	input interface{}
+	err   error
+}
+
+// AccessLogMiddleware calls logRequest after each request served by use case handler.
+//
+// It is to be added with chirouter.Wrapper.Wrap, route information of handler is used to
+// populate AccessLog.
+func AccessLogMiddleware(logRequest func(r *http.Request, l AccessLog)) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		if IsWrapperChecker(h) {
+			return h
+		}
+
+		var (
+			withRoute rest.HandlerWithRoute
+			handler   *Handler
+		)
+
+		if !HandlerAs(h, &withRoute) || !HandlerAs(h, &handler) {
+			return h
+		}
+
+		tpl := AccessLog{
+			Method:  withRoute.RouteMethod(),
+			Pattern: withRoute.RoutePattern(),
+		}
+
+		var hasName usecase.HasName
+		if usecase.As(handler.UseCase(), &hasName) {
+			tpl.OperationID = hasName.Name()
+		}
+
+		UseCaseMiddlewares(usecase.MiddlewareFunc(func(next usecase.Interactor) usecase.Interactor {
+			return usecase.Interact(func(ctx context.Context, input, output interface{}) error {
+				if st, ok := ctx.Value(accessLogCtxKey{}).(*accessLogState); ok {
+					st.input = input
+				}
+
+				return next.Interact(ctx, input, output)
+			})
+		}))(h)
+
+		handler.PhaseHooks = append(handler.PhaseHooks, func(ctx context.Context, phase Phase) (context.Context, func(err error)) {
+			if phase != PhaseEncode {
+				return ctx, func(error) {}
+			}
+
+			return ctx, func(err error) {
+				if st, ok := ctx.Value(accessLogCtxKey{}).(*accessLogState); ok && err != nil {
+					st.err = err
+				}
+			}
+		})
+
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			st := &accessLogState{}
+			sw := &StatusWriter{ResponseWriter: rw}
+
+			h.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), accessLogCtxKey{}, st)))
+
+			l := tpl
+			l.Latency = time.Since(start)
+			l.Status = sw.StatusCode()
+			l.Input = st.input
+			l.Err = st.err
+
+			logRequest(r, l)
+		})
+	}
+}
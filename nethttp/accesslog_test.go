@@ -0,0 +1,53 @@
+package nethttp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/rest/nethttp"
+)
+
+func TestAccessLog_InputSummary(t *testing.T) {
+	type credentials struct {
+		Login    string `json:"login"`
+		Password string `json:"password" redact:"true"`
+	}
+
+	type input struct {
+		Name     string                 `query:"name"`
+		Since    time.Time              `query:"since"`
+		Auth     credentials            `json:"auth"`
+		Backup   *credentials           `json:"backup"`
+		Accounts []credentials          `json:"accounts"`
+		ByHost   map[string]credentials `json:"byHost"`
+		Any      interface{}            `json:"any"`
+		Tags     []string               `json:"tags"`
+	}
+
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	l := nethttp.AccessLog{Input: input{
+		Name:     "foo",
+		Since:    since,
+		Auth:     credentials{Login: "a", Password: "secret"},
+		Backup:   &credentials{Login: "b", Password: "secret"},
+		Accounts: []credentials{{Login: "c", Password: "secret"}},
+		ByHost:   map[string]credentials{"example.com": {Login: "d", Password: "secret"}},
+		Any:      &credentials{Login: "e", Password: "secret"},
+		Tags:     []string{"x"},
+	}}
+
+	assert.Equal(t, map[string]interface{}{
+		"name":     "foo",
+		"since":    since,
+		"auth":     map[string]interface{}{"login": "a", "password": nethttp.Redacted},
+		"backup":   map[string]interface{}{"login": "b", "password": nethttp.Redacted},
+		"accounts": []interface{}{map[string]interface{}{"login": "c", "password": nethttp.Redacted}},
+		"byHost": map[string]interface{}{
+			"example.com": map[string]interface{}{"login": "d", "password": nethttp.Redacted},
+		},
+		"any":  map[string]interface{}{"login": "e", "password": nethttp.Redacted},
+		"tags": []string{"x"},
+	}, l.InputSummary())
+}
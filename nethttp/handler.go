@@ -2,9 +2,11 @@ package nethttp
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/swaggest/rest"
 	"github.com/swaggest/usecase"
@@ -41,6 +43,13 @@ func (h *Handler) UseCase() usecase.Interactor {
 // SetUseCase prepares handler for a use case.
 func (h *Handler) SetUseCase(useCase usecase.Interactor) {
 	h.useCase = useCase
+	h.deprecation, h.deprecated = rest.UseCaseDeprecation(useCase)
+	h.useCaseTimeout = 0
+
+	var withTimeout rest.WithTimeout
+	if usecase.As(useCase, &withTimeout) {
+		h.useCaseTimeout = withTimeout.Timeout()
+	}
 
 	h.setupInputBuffer()
 	h.setupOutputBuffer()
@@ -55,6 +64,18 @@ type Handler struct {
 	// HandleErrResponse allows control of error response processing.
 	HandleErrResponse func(w http.ResponseWriter, r *http.Request, err error)
 
+	// HandleInternalError receives failures that can not be served with response, default is log.Println.
+	HandleInternalError func(r *http.Request, err error)
+
+	// OnDeprecatedCall is called for each request to a deprecated use case, if set.
+	OnDeprecatedCall func(r *http.Request, d rest.Deprecation)
+
+	// StrictSunset enables 410 Gone responses for a deprecated use case after its sunset date.
+	StrictSunset bool
+
+	// PhaseHooks are called for each phase of request processing.
+	PhaseHooks []PhaseHook
+
 	// requestDecoder maps data from http.Request into structured Go input value.
 	requestDecoder RequestDecoder
 
@@ -65,6 +86,11 @@ type Handler struct {
 
 	useCase usecase.Interactor
 
+	deprecation rest.Deprecation
+	deprecated  bool
+
+	useCaseTimeout time.Duration
+
 	inputBufferType reflect.Type
 	inputIsPtr      bool
 
@@ -83,13 +109,23 @@ func (h *Handler) SetRequestDecoder(requestDecoder RequestDecoder) {
 	h.requestDecoder = requestDecoder
 }
 
+// RequestDecoder returns request decoder, it is nil until handler is prepared with RequestDecoderMiddleware.
+func (h *Handler) RequestDecoder() RequestDecoder {
+	return h.requestDecoder
+}
+
 func (h *Handler) decodeRequest(r *http.Request) (interface{}, error) {
 	if h.requestDecoder == nil {
 		panic("request decoder is not initialized, please use SetRequestDecoder")
 	}
 
+	validator := h.ReqValidator
+	if validator != nil && len(h.PhaseHooks) > 0 {
+		validator = phaseValidator{Validator: validator, ctx: r.Context(), h: h}
+	}
+
 	iv := reflect.New(h.inputBufferType)
-	err := h.requestDecoder.Decode(r, iv.Interface(), h.ReqValidator)
+	err := h.requestDecoder.Decode(r, iv.Interface(), validator)
 
 	if !h.inputIsPtr {
 		return iv.Elem().Interface(), err
@@ -111,11 +147,17 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	output = h.responseEncoder.MakeOutput(w, h.HandlerTrait)
 
+	if h.deprecated && !h.serveDeprecated(w, r) {
+		return
+	}
+
 	if h.inputBufferType != nil {
-		input, err = h.decodeRequest(r)
+		ctx, finish := h.startPhase(r.Context(), PhaseDecode)
+		input, err = h.decodeRequest(withContext(r, ctx))
+		finish(err)
 
 		if r.MultipartForm != nil {
-			defer closeMultipartForm(r)
+			defer h.closeMultipartForm(r)
 		}
 
 		if err != nil {
@@ -123,16 +165,73 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 			return
 		}
+
+		if fs, ok := input.(rest.OutputFieldsSelector); ok {
+			if fields := fs.SelectedOutputFields(); len(fields) > 0 {
+				r = r.WithContext(rest.WithOutputFields(r.Context(), fields))
+			}
+		}
 	}
 
-	err = h.useCase.Interact(r.Context(), input, output)
+	ctx, finish := h.startPhase(r.Context(), PhaseInteract)
+	err = h.interact(ctx, input, output)
+	finish(err)
+
 	if err != nil {
 		h.handleErrResponse(w, r, err)
 
 		return
 	}
 
-	h.responseEncoder.WriteSuccessfulResponse(w, r, output, h.HandlerTrait)
+	ctx, finish = h.startPhase(r.Context(), PhaseEncode)
+	h.responseEncoder.WriteSuccessfulResponse(w, withContext(r, ctx), output, h.HandlerTrait)
+	finish(nil)
+}
+
+// interact invokes use case within a time limit, if configured.
+func (h *Handler) interact(ctx context.Context, input, output interface{}) error {
+	timeout := h.Timeout
+	if timeout == 0 {
+		timeout = h.useCaseTimeout
+	}
+
+	if timeout <= 0 {
+		return h.useCase.Interact(ctx, input, output)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := h.useCase.Interact(ctx, input, output)
+
+	// Interaction is synchronous, so response is not written concurrently after timeout.
+	// Errors that are not caused by deadline are kept as is.
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && (err == nil || errors.Is(err, context.DeadlineExceeded)) {
+		if err == nil {
+			err = ctx.Err()
+		}
+
+		return status.Wrap(err, status.DeadlineExceeded)
+	}
+
+	return err
+}
+
+// serveDeprecated emits deprecation headers, it returns false if use case is not available anymore.
+func (h *Handler) serveDeprecated(w http.ResponseWriter, r *http.Request) bool {
+	if h.OnDeprecatedCall != nil {
+		h.OnDeprecatedCall(r, h.deprecation)
+	}
+
+	h.deprecation.SetHeaders(w.Header())
+
+	if h.StrictSunset && h.deprecation.IsSunset(time.Now()) {
+		h.handleErrResponse(w, r, rest.HTTPCodeAsError(http.StatusGone))
+
+		return false
+	}
+
+	return true
 }
 
 func (h *Handler) handleErrResponseDefault(w http.ResponseWriter, r *http.Request, err error) {
@@ -151,6 +250,11 @@ func (h *Handler) handleErrResponseDefault(w http.ResponseWriter, r *http.Reques
 }
 
 func (h *Handler) handleErrResponse(w http.ResponseWriter, r *http.Request, err error) {
+	ctx, finish := h.startPhase(r.Context(), PhaseEncode)
+	defer finish(err)
+
+	r = withContext(r, ctx)
+
 	if h.HandleErrResponse != nil {
 		h.HandleErrResponse(w, r, err)
 
@@ -160,8 +264,14 @@ func (h *Handler) handleErrResponse(w http.ResponseWriter, r *http.Request, err
 	h.handleErrResponseDefault(w, r, err)
 }
 
-func closeMultipartForm(r *http.Request) {
+func (h *Handler) closeMultipartForm(r *http.Request) {
 	if err := r.MultipartForm.RemoveAll(); err != nil {
+		if h.HandleInternalError != nil {
+			h.HandleInternalError(r, err)
+
+			return
+		}
+
 		log.Println(err)
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,6 +16,7 @@ import (
 	"github.com/swaggest/rest/request"
 	"github.com/swaggest/rest/response"
 	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
 )
 
 type Input struct {
@@ -344,3 +346,163 @@ func TestOptionsMiddleware(t *testing.T) {
 	assert.EqualError(t, loggedErr, "failed")
 	assert.Equal(t, `{"foo":"failed"}`+"\n", rw.Body.String())
 }
+
+func TestHandler_ServeHTTP_deprecation(t *testing.T) {
+	u := usecase.NewIOI(nil, new(Output), func(_ context.Context, _, output interface{}) error {
+		output.(*Output).Value = "ok"
+
+		return nil
+	})
+
+	d := rest.Deprecation{
+		Since:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Link:   "/v2/items",
+	}
+
+	var calls int
+
+	h := nethttp.NewHandler(usecase.Wrap(u, d), nethttp.OnDeprecatedCall(func(_ *http.Request, dd rest.Deprecation) {
+		assert.Equal(t, d, dd)
+
+		calls++
+	}))
+	h.SetResponseEncoder(&response.Encoder{})
+
+	req, err := http.NewRequest(http.MethodGet, "/test", nil)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, `{"value":"ok"}`+"\n", rw.Body.String())
+	assert.Equal(t, "@1704067200", rw.Header().Get("Deprecation"))
+	assert.Equal(t, "Wed, 01 Jan 2025 00:00:00 GMT", rw.Header().Get("Sunset"))
+	assert.Equal(t, `</v2/items>; rel="successor-version"`, rw.Header().Get("Link"))
+	assert.Equal(t, 1, calls)
+
+	nethttp.StrictSunset()(h)
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusGone, rw.Code)
+	assert.Equal(t, `{"error":"Gone"}`+"\n", rw.Body.String())
+	assert.Equal(t, "@1704067200", rw.Header().Get("Deprecation"))
+	assert.Equal(t, 2, calls)
+}
+
+func TestOnPhase(t *testing.T) {
+	u := usecase.NewIOI(new(Input), new(Output), func(_ context.Context, _, _ interface{}) error {
+		return errors.New("failed")
+	})
+
+	var phases []string
+
+	h := nethttp.NewHandler(u, nethttp.OnPhase(func(ctx context.Context, phase nethttp.Phase) (context.Context, func(err error)) {
+		phases = append(phases, "start "+string(phase))
+
+		return ctx, func(err error) {
+			if err != nil {
+				phases = append(phases, "finish "+string(phase)+": "+err.Error())
+			} else {
+				phases = append(phases, "finish "+string(phase))
+			}
+		}
+	}), func(h *nethttp.Handler) {
+		h.ReqValidator = rest.ValidatorFunc(func(_ rest.ParamIn, _ map[string]interface{}) error {
+			return nil
+		})
+	})
+	h.SetResponseEncoder(&response.Encoder{})
+	h.SetRequestDecoder(request.DecoderFunc(func(_ *http.Request, _ interface{}, validator rest.Validator) error {
+		return validator.ValidateData(rest.ParamInQuery, nil)
+	}))
+
+	req, err := http.NewRequest(http.MethodGet, "/test", nil)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	assert.Equal(t, []string{
+		"start decode",
+		"start validate",
+		"finish validate",
+		"finish decode",
+		"start interact",
+		"finish interact: failed",
+		"start encode",
+		"finish encode: failed",
+	}, phases)
+}
+
+type timeoutUseCase struct {
+	usecase.IOInteractor
+	timeout time.Duration
+}
+
+func (u timeoutUseCase) Timeout() time.Duration {
+	return u.timeout
+}
+
+func TestHandler_ServeHTTP_timeout(t *testing.T) {
+	u := usecase.NewIOI(nil, new(Output), func(ctx context.Context, _, output interface{}) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			output.(*Output).Value = "late"
+
+			return nil
+		}
+	})
+
+	h := nethttp.NewHandler(u, nethttp.Timeout(time.Millisecond))
+	h.SetResponseEncoder(&response.Encoder{})
+
+	req, err := http.NewRequest(http.MethodGet, "/test", nil)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rw.Code)
+	assert.Equal(t, `{"status":"DEADLINE_EXCEEDED","error":"deadline exceeded: context deadline exceeded"}`+"\n",
+		rw.Body.String())
+
+	// Use case timeout is applied if handler timeout is not set.
+	h = nethttp.NewHandler(timeoutUseCase{IOInteractor: u, timeout: time.Millisecond})
+	h.SetResponseEncoder(&response.Encoder{})
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rw.Code)
+
+	// Handler timeout overrides use case timeout.
+	h = nethttp.NewHandler(timeoutUseCase{IOInteractor: u, timeout: time.Millisecond}, nethttp.Timeout(time.Minute))
+	h.SetResponseEncoder(&response.Encoder{})
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, `{"value":"late"}`+"\n", rw.Body.String())
+
+	// Error that is not caused by deadline is not wrapped.
+	h = nethttp.NewHandler(usecase.NewIOI(nil, new(Output), func(ctx context.Context, _, _ interface{}) error {
+		<-ctx.Done()
+
+		return status.Wrap(errors.New("no luck"), status.NotFound)
+	}), nethttp.Timeout(time.Millisecond))
+	h.SetResponseEncoder(&response.Encoder{})
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	assert.Equal(t, `{"status":"NOT_FOUND","error":"not found: no luck"}`+"\n", rw.Body.String())
+}
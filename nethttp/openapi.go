@@ -213,3 +213,10 @@ func securityMiddleware(s *openapi.Collector, name string, cfg MiddlewareConfig)
 		return nil
 	})
 }
+
+// OpenAPIDefaultsMiddleware merges group defaults into OpenAPI operations of handlers.
+//
+// It can be added with Use or Wrap to a route group, sub-router or mounted router.
+func OpenAPIDefaultsMiddleware(s *openapi.Collector, d openapi.GroupDefaults) func(http.Handler) http.Handler {
+	return OpenAPIAnnotationsMiddleware(s, d.Annotate)
+}
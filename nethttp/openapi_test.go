@@ -5,12 +5,17 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/swaggest/assertjson"
 	oapi "github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/chirouter"
 	"github.com/swaggest/rest/nethttp"
 	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/rest/web"
 	"github.com/swaggest/usecase"
 )
 
@@ -115,3 +120,98 @@ func TestOpenAPIMiddleware(t *testing.T) {
 	 }
 	}`), sp, string(sp))
 }
+
+func TestOpenAPIDefaultsMiddleware(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	listUsers := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *[]user) error { return nil })
+	listUsers.SetName("listUsers")
+	listUsers.SetTags("Users")
+
+	dailyReport := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *user) error { return nil })
+	dailyReport.SetName("dailyReport")
+
+	health := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error { return nil })
+	health.SetName("health")
+
+	me := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *user) error { return nil })
+	me.SetName("me")
+
+	adminDefaults := nethttp.OpenAPIDefaultsMiddleware(s.OpenAPICollector, openapi.GroupDefaults{
+		Tags:      []string{"Admin"},
+		Security:  map[string][]string{"adminAuth": nil, "tenantKey": {"admin"}},
+		Responses: map[int]interface{}{http.StatusForbidden: rest.ErrResponse{}, http.StatusOK: user{}},
+		Servers:   []string{"https://admin.example.com"},
+		Parameters: new(struct {
+			Tenant string `header:"X-Tenant" required:"true"`
+		}),
+	})
+
+	s.Route("/admin", func(r chi.Router) {
+		r.Use(adminDefaults)
+
+		// Defaults that are applied twice are not duplicated.
+		r.Group(func(r chi.Router) {
+			r.Use(adminDefaults)
+			r.Method(http.MethodGet, "/users", nethttp.NewHandler(listUsers))
+		})
+
+		// Security of operation takes precedence.
+		r.With(nethttp.HTTPBearerSecurityMiddleware(s.OpenAPICollector, "userAuth", "", "")).
+			Method(http.MethodGet, "/me", nethttp.NewHandler(me))
+	})
+
+	reports := chirouter.NewWrapper(chi.NewRouter())
+	reports.Use(nethttp.OpenAPIDefaultsMiddleware(s.OpenAPICollector, openapi.GroupDefaults{
+		Tags: []string{"Reports"},
+	}))
+	reports.Method(http.MethodGet, "/daily", nethttp.NewHandler(dailyReport))
+	s.Mount("/reports", reports)
+
+	s.Get("/health", health)
+
+	assertjson.EqMarshal(t, `{
+	  "/admin/me":{
+		"get":{
+		  "tags":["Admin"],"summary":"<ignore-diff>","operationId":"me",
+		  "parameters":[{"name":"X-Tenant","in":"header","required":true,"schema":{"type":"string"}}],
+		  "responses":{"200":"<ignore-diff>","401":"<ignore-diff>","403":"<ignore-diff>"},
+		  "security":[{"userAuth":[]}],
+		  "servers":[{"url":"https://admin.example.com"}]
+		}
+	  },
+	  "/admin/users":{
+		"get":{
+		  "tags":["Users","Admin"],"summary":"<ignore-diff>","operationId":"listUsers",
+		  "parameters":[{"name":"X-Tenant","in":"header","required":true,"schema":{"type":"string"}}],
+		  "responses":{
+			"200":{
+			  "description":"OK",
+			  "content":{
+				"application/json":{
+				  "schema":{"items":{"$ref":"#/components/schemas/NethttpTestUser"},"type":"array"}
+				}
+			  }
+			},
+			"403":{
+			  "description":"Forbidden",
+			  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/RestErrResponse"}}}
+			}
+		  },
+		  "security":[{"adminAuth":[],"tenantKey":["admin"]}],
+		  "servers":[{"url":"https://admin.example.com"}]
+		}
+	  },
+	  "/health":{"get":{"summary":"<ignore-diff>","operationId":"health","responses":"<ignore-diff>"}},
+	  "/reports/daily":{
+		"get":{
+		  "tags":["Reports"],"summary":"<ignore-diff>","operationId":"dailyReport",
+		  "responses":"<ignore-diff>"
+		}
+	  }
+	}`, s.OpenAPICollector.Reflector().Spec.Paths.MapOfPathItemValues)
+}
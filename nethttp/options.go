@@ -3,6 +3,7 @@ package nethttp
 import (
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/openapi3"
@@ -27,6 +28,28 @@ func OptionsMiddleware(options ...func(h *Handler)) func(h http.Handler) http.Ha
 	}
 }
 
+// OnDeprecatedCall sets a hook that is called for each request to a deprecated use case,
+// for example to log or count such calls.
+func OnDeprecatedCall(hook func(r *http.Request, d rest.Deprecation)) func(h *Handler) {
+	return func(h *Handler) {
+		h.OnDeprecatedCall = hook
+	}
+}
+
+// StrictSunset makes handler of a deprecated use case respond with 410 Gone after sunset date.
+func StrictSunset() func(h *Handler) {
+	return func(h *Handler) {
+		h.StrictSunset = true
+	}
+}
+
+// Timeout limits duration of use case interaction, on expiry response is served with 504 Gateway Timeout.
+func Timeout(timeout time.Duration) func(h *Handler) {
+	return func(h *Handler) {
+		h.Timeout = timeout
+	}
+}
+
 // AnnotateOpenAPIOperation allows customization of OpenAPI operation, that is reflected from the Handler.
 func AnnotateOpenAPIOperation(annotations ...func(oc openapi.OperationContext) error) func(h *Handler) {
 	return func(h *Handler) {
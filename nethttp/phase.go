@@ -0,0 +1,96 @@
+package nethttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/swaggest/rest"
+)
+
+// Phase is a stage of request processing in Handler.
+type Phase string
+
+// Request processing phases.
+const (
+	// PhaseDecode is decoding of request into use case input, it includes validation.
+	PhaseDecode = Phase("decode")
+
+	// PhaseValidate is validation of request data, it happens during decoding for every validated location.
+	PhaseValidate = Phase("validate")
+
+	// PhaseInteract is invocation of use case interactor.
+	PhaseInteract = Phase("interact")
+
+	// PhaseEncode is writing of successful or error response.
+	PhaseEncode = Phase("encode")
+)
+
+// PhaseHook is called when handler starts a phase of request processing.
+//
+// It may return a derived context (for example with a tracing span) that is used within the phase,
+// and it must return a function that is called when phase is finished with an error of phase, if any.
+type PhaseHook func(ctx context.Context, phase Phase) (context.Context, func(err error))
+
+// OnPhase adds a hook to observe phases of request processing, for example to trace or measure them.
+func OnPhase(hook PhaseHook) func(h *Handler) {
+	return func(h *Handler) {
+		h.PhaseHooks = append(h.PhaseHooks, hook)
+	}
+}
+
+func (h *Handler) startPhase(ctx context.Context, phase Phase) (context.Context, func(err error)) {
+	if len(h.PhaseHooks) == 0 {
+		return ctx, func(error) {}
+	}
+
+	if len(h.PhaseHooks) == 1 {
+		return h.PhaseHooks[0](ctx, phase)
+	}
+
+	finish := make([]func(err error), 0, len(h.PhaseHooks))
+
+	for _, hook := range h.PhaseHooks {
+		var f func(err error)
+
+		ctx, f = hook(ctx, phase)
+		finish = append(finish, f)
+	}
+
+	return ctx, func(err error) {
+		for i := len(finish) - 1; i >= 0; i-- {
+			finish[i](err)
+		}
+	}
+}
+
+// phaseValidator reports validation phases of a wrapped validator.
+type phaseValidator struct {
+	rest.Validator
+	ctx context.Context
+	h   *Handler
+}
+
+func (v phaseValidator) ValidateData(in rest.ParamIn, namedData map[string]interface{}) error {
+	_, finish := v.h.startPhase(v.ctx, PhaseValidate)
+	err := v.Validator.ValidateData(in, namedData)
+	finish(err)
+
+	return err
+}
+
+func (v phaseValidator) ValidateJSONBody(jsonBody []byte) error {
+	_, finish := v.h.startPhase(v.ctx, PhaseValidate)
+	err := v.Validator.ValidateJSONBody(jsonBody)
+	finish(err)
+
+	return err
+}
+
+// withContext returns request with context, request is not copied if context is not changed.
+func withContext(r *http.Request, ctx context.Context) *http.Request {
+	if ctx == r.Context() {
+		return r
+	}
+
+	return r.WithContext(ctx)
+}
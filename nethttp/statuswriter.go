@@ -0,0 +1,81 @@
+package nethttp
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+var (
+	_ http.Flusher  = &StatusWriter{}
+	_ http.Hijacker = &StatusWriter{}
+)
+
+// StatusWriter captures status and size of response, it can be used in middlewares to observe responses.
+//
+// Flush and Hijack are passed to underlying http.ResponseWriter.
+type StatusWriter struct {
+	http.ResponseWriter
+
+	// Status is the first status code written, or zero if nothing was written.
+	Status int
+
+	// Written is a number of bytes written to response body.
+	Written int
+}
+
+// WriteHeader captures status code and writes it to underlying http.ResponseWriter.
+func (w *StatusWriter) WriteHeader(statusCode int) {
+	if w.Status == 0 {
+		w.Status = statusCode
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write counts written bytes, implicit status is http.StatusOK.
+func (w *StatusWriter) Write(data []byte) (int, error) {
+	if w.Status == 0 {
+		w.Status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(data)
+	w.Written += n
+
+	return n, err
+}
+
+// StatusCode returns response status, http.StatusOK is returned if nothing was written.
+func (w *StatusWriter) StatusCode() int {
+	if w.Status == 0 {
+		return http.StatusOK
+	}
+
+	return w.Status
+}
+
+// Unwrap returns original http.ResponseWriter for http.ResponseController.
+func (w *StatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush implements http.Flusher, it is a no-op if underlying http.ResponseWriter can not flush.
+func (w *StatusWriter) Flush() {
+	if w.Status == 0 {
+		w.Status = http.StatusOK
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, http.ErrNotSupported is returned if underlying
+// http.ResponseWriter can not hijack connection.
+func (w *StatusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, http.ErrNotSupported
+}
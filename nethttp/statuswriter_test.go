@@ -0,0 +1,57 @@
+package nethttp_test
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/rest/nethttp"
+)
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+
+	return nil, nil, nil
+}
+
+func TestStatusWriter_Flush(t *testing.T) {
+	rec := httptest.NewRecorder()
+	sw := &nethttp.StatusWriter{ResponseWriter: rec}
+
+	var rw http.ResponseWriter = sw
+
+	f, ok := rw.(http.Flusher)
+	require.True(t, ok)
+
+	f.Flush()
+
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, http.StatusOK, sw.Status)
+}
+
+func TestStatusWriter_Hijack(t *testing.T) {
+	rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	var rw http.ResponseWriter = &nethttp.StatusWriter{ResponseWriter: rec}
+
+	h, ok := rw.(http.Hijacker)
+	require.True(t, ok)
+
+	_, _, err := h.Hijack()
+	require.NoError(t, err)
+	assert.True(t, rec.hijacked)
+
+	sw := &nethttp.StatusWriter{ResponseWriter: httptest.NewRecorder()}
+	_, _, err = sw.Hijack()
+	assert.True(t, errors.Is(err, http.ErrNotSupported))
+}
@@ -84,6 +84,25 @@ func HandlerAs(handler http.Handler, target interface{}) bool {
 	return false
 }
 
+// WrapperNames returns function names of unwrappable middlewares of handler.
+//
+// Names are ordered as middlewares are invoked during request processing, outermost first.
+func WrapperNames(handler http.Handler) []string {
+	var names []string
+
+	for {
+		wrap, isWrap := handler.(*wrappedHandler)
+		if !isWrap {
+			break
+		}
+
+		names = append(names, wrap.mwName)
+		handler = wrap.wrapped
+	}
+
+	return names
+}
+
 var handlerType = reflect.TypeOf((*http.Handler)(nil)).Elem()
 
 type wrappedHandler struct {
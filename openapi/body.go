@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"encoding/json"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/rest"
+)
+
+// setupJSONBody documents request body of input that implements rest.InputWithJSONBody.
+func (c *Collector) setupJSONBody(oc openapi.OperationContext, input rest.InputWithJSONBody) {
+	mediaType := input.JSONBodyMediaType()
+
+	oc.AddReqStructure(nil, func(cu *openapi.ContentUnit) {
+		cu.ContentType = mediaType
+		cu.Customize = func(cor openapi.ContentOrReference) {
+			schema, err := input.JSONBodySchema(c.refl().JSONSchemaReflector())
+			if err != nil {
+				panic("reflect request body schema: " + err.Error())
+			}
+
+			setRequestBodySchema(cor, mediaType, schema)
+		}
+	})
+}
+
+func setRequestBodySchema(cor openapi.ContentOrReference, mediaType string, schema jsonschema.Schema) {
+	switch rb := cor.(type) {
+	case *openapi3.RequestBodyOrRef:
+		if rb.RequestBody == nil {
+			return
+		}
+
+		s := openapi3.SchemaOrRef{}
+		s.FromJSONSchema(schema.ToSchemaOrBool())
+
+		rb.RequestBody.WithRequired(true)
+		rb.RequestBody.WithContentItem(mediaType, openapi3.MediaType{Schema: &s})
+	case *openapi31.RequestBodyOrReference:
+		if rb.RequestBody == nil {
+			return
+		}
+
+		var s map[string]interface{}
+
+		b, err := json.Marshal(schema)
+		if err == nil {
+			err = json.Unmarshal(b, &s)
+		}
+
+		if err != nil {
+			panic("convert request body schema: " + err.Error())
+		}
+
+		rb.RequestBody.WithRequired(true)
+		rb.RequestBody.WithContentItem(mediaType, openapi31.MediaType{Schema: s})
+	}
+}
+
+// provideJSONBodySchema adds request body schema of input that implements rest.InputWithJSONBody to validator.
+func (c *Collector) provideJSONBodySchema(input interface{}, validator rest.JSONSchemaValidator) error {
+	withJSONBody, ok := input.(rest.InputWithJSONBody)
+	if !ok {
+		return nil
+	}
+
+	schema, err := withJSONBody.JSONBodySchema(c.refl().JSONSchemaReflector())
+	if err != nil {
+		return err
+	}
+
+	schemaData, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+
+	return validator.AddSchema(rest.ParamInBody, "body", schemaData, true)
+}
@@ -0,0 +1,350 @@
+package openapi
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+const componentsSchemasPrefix = "#/components/schemas/"
+
+// JSONSchemaBundle returns standalone JSON Schema documents of reflected types keyed by component name.
+//
+// Schemas refer to each other with relative file names "<name>.json". In addition to
+// components of OpenAPI document, bundle has "<OperationID>Params" schemas that group
+// request parameters of an operation by location (path, query, header, cookie).
+func (c *Collector) JSONSchemaBundle() (map[string][]byte, error) {
+	spec, err := c.CompactJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas    map[string]interface{}            `json:"schemas"`
+			Parameters map[string]map[string]interface{} `json:"parameters"`
+		} `json:"components"`
+	}
+
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, err
+	}
+
+	dialect := "http://json-schema.org/draft-07/schema#"
+	if _, ok := c.refl().(*openapi31.Reflector); ok {
+		dialect = "https://json-schema.org/draft/2020-12/schema"
+	}
+
+	schemas := make(map[string]interface{}, len(doc.Components.Schemas))
+	for name, s := range doc.Components.Schemas {
+		schemas[name] = s
+	}
+
+	for path, pathItem := range doc.Paths {
+		var common []map[string]interface{}
+
+		if raw, ok := pathItem["parameters"]; ok {
+			if err := json.Unmarshal(raw, &common); err != nil {
+				return nil, fmt.Errorf("params of %s: %w", path, err)
+			}
+		}
+
+		for method, op := range pathItem {
+			name, params, err := paramsSchema(method, path, op, common, doc.Components.Parameters)
+			if err != nil {
+				return nil, fmt.Errorf("params of %s %s: %w", method, path, err)
+			}
+
+			if params == nil {
+				continue
+			}
+
+			if _, found := schemas[name]; found {
+				return nil, fmt.Errorf("duplicate schema name %s for params of %s %s", name, method, path)
+			}
+
+			schemas[name] = params
+		}
+	}
+
+	bundle := make(map[string][]byte, len(schemas))
+
+	for name, s := range schemas {
+		m, ok := bundleSchema(s).(map[string]interface{})
+		if !ok {
+			m = map[string]interface{}{"allOf": []interface{}{s}}
+		}
+
+		m["$schema"] = dialect
+		m["$id"] = name + ".json"
+
+		if bundle[name], err = json.MarshalIndent(m, "", " "); err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", name, err)
+		}
+	}
+
+	return bundle, nil
+}
+
+// WriteJSONSchemaBundle writes JSON Schema bundle to directory as "<name>.json" files.
+func (c *Collector) WriteJSONSchemaBundle(dir string) error {
+	bundle, err := c.JSONSchemaBundle()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	for name, data := range bundle {
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), data, 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteJSONSchemaArchive writes JSON Schema bundle as zip archive of "<name>.json" files.
+func (c *Collector) WriteJSONSchemaArchive(w io.Writer) error {
+	bundle, err := c.JSONSchemaBundle()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(bundle))
+	for name := range bundle {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+
+	for _, name := range names {
+		f, err := zw.Create(name + ".json")
+		if err != nil {
+			return err
+		}
+
+		if _, err := f.Write(bundle[name]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// mergeParams resolves references and overrides common parameters with operation parameters.
+func mergeParams(
+	common, params []map[string]interface{},
+	components map[string]map[string]interface{},
+) ([]map[string]interface{}, error) {
+	res := make([]map[string]interface{}, 0, len(common)+len(params))
+	pos := make(map[string]int, len(common)+len(params))
+
+	for _, p := range append(append([]map[string]interface{}(nil), common...), params...) {
+		if ref, ok := p["$ref"].(string); ok {
+			p = components[strings.TrimPrefix(ref, "#/components/parameters/")]
+			if p == nil {
+				return nil, fmt.Errorf("unresolved parameter reference %s", ref)
+			}
+		}
+
+		in, _ := p["in"].(string)      //nolint:errcheck // Zero value is handled.
+		pName, _ := p["name"].(string) //nolint:errcheck // Zero value is handled.
+
+		if i, found := pos[in+"/"+pName]; found {
+			res[i] = p
+
+			continue
+		}
+
+		pos[in+"/"+pName] = len(res)
+		res = append(res, p)
+	}
+
+	return res, nil
+}
+
+// paramsSchema makes an object schema of operation parameters grouped by location.
+//
+// Common parameters of path item are included unless overridden by operation parameter with same location and name.
+func paramsSchema(
+	method, path string,
+	op json.RawMessage,
+	common []map[string]interface{},
+	components map[string]map[string]interface{},
+) (string, map[string]interface{}, error) {
+	var o struct {
+		ID         string                   `json:"operationId"`
+		Parameters []map[string]interface{} `json:"parameters"`
+	}
+
+	// Path item may have fields that are not operations.
+	if err := json.Unmarshal(op, &o); err != nil || len(o.Parameters)+len(common) == 0 {
+		return "", nil, nil //nolint:nilerr // Not an operation.
+	}
+
+	name := o.ID
+	if name == "" {
+		name = method + " " + path
+	}
+
+	name = camelName(name) + "Params"
+
+	params, err := mergeParams(common, o.Parameters, components)
+	if err != nil {
+		return "", nil, err
+	}
+
+	groups := map[string]map[string]interface{}{}
+
+	for _, p := range params {
+		in, _ := p["in"].(string)      //nolint:errcheck // Zero value is handled.
+		pName, _ := p["name"].(string) //nolint:errcheck // Zero value is handled.
+
+		if in == "" || pName == "" {
+			continue
+		}
+
+		s := paramSchema(p)
+
+		g := groups[in]
+		if g == nil {
+			g = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+			groups[in] = g
+		}
+
+		g["properties"].(map[string]interface{})[pName] = s //nolint:errcheck // Type is known.
+
+		if required, _ := p["required"].(bool); required { //nolint:errcheck // Zero value is handled.
+			req, _ := g["required"].([]interface{}) //nolint:errcheck // Zero value is handled.
+			g["required"] = append(req, pName)
+		}
+	}
+
+	if len(groups) == 0 {
+		return "", nil, nil
+	}
+
+	properties := make(map[string]interface{}, len(groups))
+	for in, g := range groups {
+		properties[in] = g
+	}
+
+	return name, map[string]interface{}{"type": "object", "properties": properties}, nil
+}
+
+// paramSchema returns schema of parameter from schema or content field.
+func paramSchema(p map[string]interface{}) map[string]interface{} {
+	s, _ := p["schema"].(map[string]interface{}) //nolint:errcheck // Zero value is handled.
+
+	if s == nil {
+		if content, ok := p["content"].(map[string]interface{}); ok {
+			for _, mt := range content {
+				if m, ok := mt.(map[string]interface{}); ok {
+					s, _ = m["schema"].(map[string]interface{}) //nolint:errcheck // Zero value is handled.
+				}
+			}
+		}
+	}
+
+	if s == nil {
+		s = map[string]interface{}{}
+	}
+
+	if d, ok := p["description"]; ok {
+		if _, found := s["description"]; !found {
+			s["description"] = d
+		}
+	}
+
+	return s
+}
+
+// bundleSchema rewrites component references to file names and converts OpenAPI 3.0 nullable
+// and boolean exclusiveMinimum/exclusiveMaximum.
+func bundleSchema(v interface{}) interface{} {
+	switch s := v.(type) {
+	case map[string]interface{}:
+		for k, val := range s {
+			s[k] = bundleSchema(val)
+		}
+
+		if ref, ok := s["$ref"].(string); ok && strings.HasPrefix(ref, componentsSchemasPrefix) {
+			s["$ref"] = strings.TrimPrefix(ref, componentsSchemasPrefix) + ".json"
+		}
+
+		if nullable, ok := s["nullable"].(bool); ok {
+			delete(s, "nullable")
+
+			if t, ok := s["type"].(string); ok && nullable {
+				s["type"] = []interface{}{t, "null"}
+			}
+		}
+
+		exclusiveBound(s, "exclusiveMinimum", "minimum")
+		exclusiveBound(s, "exclusiveMaximum", "maximum")
+
+		return s
+	case []interface{}:
+		for i, val := range s {
+			s[i] = bundleSchema(val)
+		}
+
+		return s
+	default:
+		return v
+	}
+}
+
+// exclusiveBound replaces OpenAPI 3.0 boolean exclusive keyword with numeric form of draft-07.
+func exclusiveBound(s map[string]interface{}, exclusive, bound string) {
+	isExclusive, ok := s[exclusive].(bool)
+	if !ok {
+		return
+	}
+
+	delete(s, exclusive)
+
+	if v, found := s[bound]; found && isExclusive {
+		s[exclusive] = v
+		delete(s, bound)
+	}
+}
+
+// camelName converts arbitrary string to CamelCase identifier.
+func camelName(s string) string {
+	var (
+		b     strings.Builder
+		upper = true
+	)
+
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
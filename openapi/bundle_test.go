@@ -0,0 +1,171 @@
+package openapi_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/usecase"
+)
+
+func TestCollector_JSONSchemaBundle(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+
+	type input struct {
+		ID      int     `path:"id"`
+		Locale  string  `query:"locale" required:"true" description:"Preferred locale."`
+		Address address `json:"address"`
+	}
+
+	type output struct {
+		Address *address `json:"address"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, _ input, _ *output) error {
+		return nil
+	})
+	u.SetName("updateUser")
+
+	c := openapi.NewCollector(openapi3.NewReflector())
+	require.NoError(t, c.CollectUseCase(http.MethodPut, "/users/{id}", u, rest.HandlerTrait{}))
+
+	bundle, err := c.JSONSchemaBundle()
+	require.NoError(t, err)
+
+	assert.Len(t, bundle, 4)
+	assertjson.Equal(t, []byte(`{
+	  "$id":"OpenapiTestInput.json","$schema":"http://json-schema.org/draft-07/schema#",
+	  "type":"object","properties":{"address":{"$ref":"OpenapiTestAddress.json"}}
+	}`), bundle["OpenapiTestInput"])
+	assertjson.Equal(t, []byte(`{
+	  "$id":"OpenapiTestOutput.json","$schema":"http://json-schema.org/draft-07/schema#",
+	  "type":"object","properties":{"address":{"$ref":"OpenapiTestAddress.json"}}
+	}`), bundle["OpenapiTestOutput"])
+	assertjson.Equal(t, []byte(`{
+	  "$id":"OpenapiTestAddress.json","$schema":"http://json-schema.org/draft-07/schema#",
+	  "type":"object","properties":{"city":{"type":"string"}}
+	}`), bundle["OpenapiTestAddress"])
+	assertjson.Equal(t, []byte(`{
+	  "$id":"UpdateUserParams.json","$schema":"http://json-schema.org/draft-07/schema#",
+	  "type":"object",
+	  "properties":{
+		"path":{"type":"object","properties":{"id":{"type":"integer"}},"required":["id"]},
+		"query":{
+		  "type":"object","properties":{"locale":{"type":"string","description":"Preferred locale."}},
+		  "required":["locale"]
+		}
+	  }
+	}`), bundle["UpdateUserParams"])
+
+	dir := t.TempDir()
+	require.NoError(t, c.WriteJSONSchemaBundle(dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, "UpdateUserParams.json"))
+	require.NoError(t, err)
+	assert.Equal(t, bundle["UpdateUserParams"], data)
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, c.WriteJSONSchemaArchive(buf))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+
+	assert.Equal(t, []string{
+		"OpenapiTestAddress.json", "OpenapiTestInput.json", "OpenapiTestOutput.json", "UpdateUserParams.json",
+	}, names)
+}
+
+func TestCollector_JSONSchemaBundle_exclusiveBounds(t *testing.T) {
+	type input struct {
+		Limit int     `query:"limit" exclusiveMinimum:"0" maximum:"100"`
+		Ratio float64 `json:"ratio" minimum:"0" exclusiveMaximum:"1"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, _ input, _ *struct{}) error {
+		return nil
+	})
+	u.SetName("tune")
+
+	c := openapi.NewCollector(openapi3.NewReflector())
+	require.NoError(t, c.CollectUseCase(http.MethodPost, "/tune", u, rest.HandlerTrait{}))
+
+	bundle, err := c.JSONSchemaBundle()
+	require.NoError(t, err)
+
+	assertjson.Equal(t, []byte(`{
+	  "$id":"OpenapiTestInput.json","$schema":"http://json-schema.org/draft-07/schema#",
+	  "type":"object","properties":{"ratio":{"type":"number","format":"double","minimum":0,"exclusiveMaximum":1}}
+	}`), bundle["OpenapiTestInput"])
+	assertjson.Equal(t, []byte(`{
+	  "$id":"TuneParams.json","$schema":"http://json-schema.org/draft-07/schema#",
+	  "type":"object",
+	  "properties":{
+		"query":{
+		  "type":"object","properties":{"limit":{"type":"integer","exclusiveMinimum":0,"maximum":100}}
+		}
+	  }
+	}`), bundle["TuneParams"])
+}
+
+func TestCollector_JSONSchemaBundle_pathItemParams(t *testing.T) {
+	type input struct {
+		ID     int    `path:"id"`
+		Locale string `query:"locale" required:"true"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, _ input, _ *struct{}) error {
+		return nil
+	})
+	u.SetName("getUser")
+
+	c := openapi.NewCollector(openapi3.NewReflector())
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/users/{id}", u, rest.HandlerTrait{}))
+
+	pi := c.Reflector().Spec.Paths.MapOfPathItemValues["/users/{id}"]
+	pi.WithParameters(
+		openapi3.Parameter{
+			Name: "X-Tenant", In: openapi3.ParameterInHeader, Required: ptr(true),
+			Schema: &openapi3.SchemaOrRef{Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeString)},
+		}.ToParameterOrRef(),
+		// Operation parameter takes precedence.
+		openapi3.Parameter{
+			Name: "locale", In: openapi3.ParameterInQuery,
+			Schema: &openapi3.SchemaOrRef{Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeInteger)},
+		}.ToParameterOrRef(),
+	)
+	c.Reflector().Spec.Paths.MapOfPathItemValues["/users/{id}"] = pi
+
+	bundle, err := c.JSONSchemaBundle()
+	require.NoError(t, err)
+
+	assertjson.Equal(t, []byte(`{
+	  "$id":"GetUserParams.json","$schema":"http://json-schema.org/draft-07/schema#",
+	  "type":"object",
+	  "properties":{
+		"header":{"type":"object","properties":{"X-Tenant":{"type":"string"}},"required":["X-Tenant"]},
+		"path":{"type":"object","properties":{"id":{"type":"integer"}},"required":["id"]},
+		"query":{"type":"object","properties":{"locale":{"type":"string"}},"required":["locale"]}
+	  }
+	}`), bundle["GetUserParams"])
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -3,18 +3,21 @@ package openapi
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/swaggest/jsonschema-go"
 	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
 	"github.com/swaggest/rest"
 	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
 )
 
 // Collector extracts OpenAPI documentation from HTTP handler and underlying use case interactor.
@@ -36,15 +39,40 @@ type Collector struct {
 	// If empty, "application/json" is used.
 	DefaultErrorResponseContentType string
 
+	// ServerURLFromRequest enables rewriting of servers in served document with URL of incoming request,
+	// X-Forwarded-Proto, X-Forwarded-Host and X-Forwarded-Prefix headers are respected for TrustedProxies.
+	ServerURLFromRequest bool
+
+	// TrustedProxies is a list of IP addresses or CIDR ranges (e.g. "10.0.0.0/8") of reverse proxies
+	// that are allowed to set X-Forwarded-* headers, headers are ignored if list is empty.
+	TrustedProxies []string
+
+	// NamingStrategy infers operation ID, summary and tags that are not provided by use case.
+	NamingStrategy NamingStrategy
+
+	// PreferNamingStrategy enables precedence of NamingStrategy over name, title and tags of use case.
+	PreferNamingStrategy bool
+
+	// StrictOperationIDs enables failure on duplicate operation ID instead of adding numeric suffix.
+	StrictOperationIDs bool
+
 	gen *openapi3.Reflector
 	ref openapi.Reflector
 
 	ocAnnotations map[string][]func(oc openapi.OperationContext) error
 	annotations   map[string][]func(*openapi3.Operation) error
-	operationIDs  map[string]bool
+	operationIDs  map[string]string
+
+	docMu           sync.Mutex
+	docGen          int
+	documents       map[string]document
+	serverDocuments map[string]document
 }
 
 // NewCollector creates an instance of OpenAPI Collector.
+//
+// Reflector is configured (once per reflector) to reflect values of rest.Optional and rest.Nullable,
+// OpenAPI 3.0 reflector is also configured to keep numeric exclusive bounds.
 func NewCollector(r openapi.Reflector) *Collector {
 	c := &Collector{
 		ref: r,
@@ -52,33 +80,62 @@ func NewCollector(r openapi.Reflector) *Collector {
 
 	if r3, ok := r.(*openapi3.Reflector); ok {
 		c.gen = r3
+
+		interceptExclusiveBounds(r3)
+	}
+
+	if r != nil {
+		interceptValueSchemas(r)
 	}
 
 	return c
 }
 
 // SpecSchema returns OpenAPI specification schema.
+//
+// Served documents are invalidated, as spec may be changed by caller.
 func (c *Collector) SpecSchema() openapi.SpecSchema {
-	return c.Refl().SpecSchema()
+	c.InvalidateCache()
+
+	return c.refl().SpecSchema()
 }
 
 // Refl returns OpenAPI reflector.
+//
+// Served documents are invalidated, as spec may be changed by caller.
 func (c *Collector) Refl() openapi.Reflector {
+	c.InvalidateCache()
+
+	return c.refl()
+}
+
+// Reflector is an accessor to OpenAPI Reflector instance.
+//
+// Served documents are invalidated, as spec may be changed by caller.
+func (c *Collector) Reflector() *openapi3.Reflector {
+	c.InvalidateCache()
+
+	return c.reflector()
+}
+
+func (c *Collector) refl() openapi.Reflector {
 	if c.ref != nil {
 		return c.ref
 	}
 
-	return c.Reflector()
+	return c.reflector()
 }
 
-// Reflector is an accessor to OpenAPI Reflector instance.
-func (c *Collector) Reflector() *openapi3.Reflector {
+func (c *Collector) reflector() *openapi3.Reflector {
 	if c.ref != nil && c.gen == nil {
 		panic(fmt.Sprintf("conflicting OpenAPI reflector supplied: %T", c.ref))
 	}
 
 	if c.gen == nil {
 		c.gen = openapi3.NewReflector()
+
+		interceptValueSchemas(c.gen)
+		interceptExclusiveBounds(c.gen)
 	}
 
 	return c.gen
@@ -109,6 +166,8 @@ func (c *Collector) AnnotateOperation(method, pattern string, setup ...func(oc o
 	}
 
 	c.ocAnnotations[method+pattern] = append(c.ocAnnotations[method+pattern], setup...)
+
+	c.InvalidateCache()
 }
 
 // HasAnnotation indicates if there is at least one annotation registered for this operation.
@@ -128,13 +187,15 @@ func (c *Collector) CollectOperation(
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.InvalidateCache()
+
 	defer func() {
 		if err != nil {
 			err = fmt.Errorf("failed to reflect API schema for %s %s: %w", method, pattern, err)
 		}
 	}()
 
-	reflector := c.Refl()
+	reflector := c.refl()
 
 	oc, err := reflector.NewOperationContext(method, pattern)
 	if err != nil {
@@ -172,13 +233,15 @@ func (c *Collector) CollectUseCase(
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.InvalidateCache()
+
 	defer func() {
 		if err != nil {
 			err = fmt.Errorf("reflect API schema for %s %s: %w", method, pattern, err)
 		}
 	}()
 
-	reflector := c.Refl()
+	reflector := c.refl()
 
 	oc, err := reflector.NewOperationContext(method, pattern)
 	if err != nil {
@@ -187,7 +250,9 @@ func (c *Collector) CollectUseCase(
 
 	c.setupInput(oc, u, h)
 	c.setupOutput(oc, u, h)
-	c.processUseCase(oc, u, h)
+	if err = c.processUseCase(oc, u, h); err != nil {
+		return err
+	}
 
 	an := append([]func(oc openapi.OperationContext) error(nil), c.ocAnnotations[method+pattern]...)
 	an = append(an, h.OpenAPIAnnotations...)
@@ -231,13 +296,15 @@ func (c *Collector) Collect(
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.InvalidateCache()
+
 	defer func() {
 		if err != nil {
 			err = fmt.Errorf("reflect API schema for %s %s: %w", method, pattern, err)
 		}
 	}()
 
-	reflector := c.Refl()
+	reflector := c.refl()
 
 	oc, err := reflector.NewOperationContext(method, pattern)
 	if err != nil {
@@ -246,7 +313,9 @@ func (c *Collector) Collect(
 
 	c.setupInput(oc, u, h)
 	c.setupOutput(oc, u, h)
-	c.processUseCase(oc, u, h)
+	if err = c.processUseCase(oc, u, h); err != nil {
+		return err
+	}
 
 	for _, setup := range c.ocAnnotations[method+pattern] {
 		err = setup(oc)
@@ -308,6 +377,19 @@ func (c *Collector) setupOutput(oc openapi.OperationContext, u usecase.Interacto
 	setupCU := func(cu *openapi.ContentUnit) {
 		cu.ContentType = contentType
 		cu.SetFieldMapping(openapi.InHeader, h.RespHeaderMapping)
+
+		_, withWebLinks := output.(rest.WithWebLinks)
+
+		if withWebLinks {
+			cu.Customize = withResponseHeaders(cu.Customize, responseHeader{
+				name:        "Link",
+				description: "Links to related resources (RFC 8288).",
+			})
+		}
+
+		if d, deprecated := rest.UseCaseDeprecation(u); deprecated {
+			cu.Customize = withResponseHeaders(cu.Customize, deprecationHeaders(d, withWebLinks)...)
+		}
 	}
 
 	if outputWithStatus, ok := output.(rest.OutputWithHTTPStatus); ok {
@@ -336,6 +418,10 @@ func (c *Collector) setupInput(oc openapi.OperationContext, u usecase.Interactor
 		oc.AddReqStructure(hasInput.InputPort(), func(cu *openapi.ContentUnit) {
 			setFieldMapping(cu, h.ReqMapping)
 		})
+
+		if withJSONBody, ok := hasInput.InputPort().(rest.InputWithJSONBody); ok {
+			c.setupJSONBody(oc, withJSONBody)
+		}
 	}
 }
 
@@ -349,51 +435,47 @@ func setFieldMapping(cu *openapi.ContentUnit, mapping rest.RequestMapping) {
 	}
 }
 
-func (c *Collector) processUseCase(oc openapi.OperationContext, u usecase.Interactor, h rest.HandlerTrait) {
+func (c *Collector) processUseCase(oc openapi.OperationContext, u usecase.Interactor, h rest.HandlerTrait) error {
 	var (
 		hasName        usecase.HasName
 		hasTitle       usecase.HasTitle
 		hasDescription usecase.HasDescription
 		hasTags        usecase.HasTags
 		hasDeprecated  usecase.HasIsDeprecated
+		naming         OperationNaming
 	)
 
-	if usecase.As(u, &hasName) {
-		id := hasName.Name()
-
-		if id != "" {
-			if c.operationIDs == nil {
-				c.operationIDs = make(map[string]bool)
-			}
-
-			idSuf := id
-			suf := 1
-
-			for c.operationIDs[idSuf] {
-				suf++
-				idSuf = id + strconv.Itoa(suf)
-			}
+	if c.NamingStrategy != nil {
+		naming = c.NamingStrategy.OperationNaming(oc.Method(), oc.PathPattern(), u)
+	}
 
-			c.operationIDs[idSuf] = true
+	id := naming.ID
+	if usecase.As(u, &hasName) && hasName.Name() != "" && (id == "" || !c.PreferNamingStrategy) {
+		id = hasName.Name()
+	}
 
-			oc.SetID(idSuf)
+	if id != "" {
+		if err := c.setOperationID(oc, id); err != nil {
+			return err
 		}
 	}
 
-	if usecase.As(u, &hasTitle) {
-		title := hasTitle.Title()
+	summary := naming.Summary
+	if usecase.As(u, &hasTitle) && hasTitle.Title() != "" && (summary == "" || !c.PreferNamingStrategy) {
+		summary = hasTitle.Title()
+	}
 
-		if title != "" {
-			oc.SetSummary(hasTitle.Title())
-		}
+	if summary != "" {
+		oc.SetSummary(summary)
 	}
 
-	if usecase.As(u, &hasTags) {
-		tags := hasTags.Tags()
+	tags := naming.Tags
+	if usecase.As(u, &hasTags) && len(hasTags.Tags()) > 0 && (len(tags) == 0 || !c.PreferNamingStrategy) {
+		tags = hasTags.Tags()
+	}
 
-		if len(tags) > 0 {
-			oc.SetTags(hasTags.Tags()...)
-		}
+	if len(tags) > 0 {
+		oc.SetTags(tags...)
 	}
 
 	if usecase.As(u, &hasDescription) {
@@ -408,7 +490,57 @@ func (c *Collector) processUseCase(oc openapi.OperationContext, u usecase.Intera
 		oc.SetIsDeprecated(true)
 	}
 
+	c.processTimeout(oc, u, h)
+
 	c.processOCExpectedErrors(oc, u, h)
+
+	return nil
+}
+
+// setOperationID sets unique operation ID, numeric suffix is added to duplicate ID unless StrictOperationIDs is set.
+//
+// ID that is already used by the same method and pattern is not a duplicate.
+// In strict mode, ID that is used by another method of the same pattern (for example, with a method-less route
+// that serves use case for all methods) gets method suffix, e.g. "getThingPut".
+func (c *Collector) setOperationID(oc openapi.OperationContext, id string) error {
+	if c.operationIDs == nil {
+		c.operationIDs = make(map[string]string)
+	}
+
+	operation := strings.ToUpper(oc.Method()) + " " + oc.PathPattern()
+
+	if c.StrictOperationIDs {
+		prev, found := c.operationIDs[id]
+
+		if found && prev != operation && strings.SplitN(prev, " ", 2)[1] == oc.PathPattern() {
+			id += upperFirst(strings.ToLower(oc.Method()))
+			prev, found = c.operationIDs[id]
+		}
+
+		if found && prev != operation {
+			return fmt.Errorf("duplicate operation ID %q, already used by %s", id, prev)
+		}
+
+		c.operationIDs[id] = operation
+
+		oc.SetID(id)
+
+		return nil
+	}
+
+	idSuf := id
+	suf := 1
+
+	for prev := c.operationIDs[idSuf]; prev != "" && prev != operation; prev = c.operationIDs[idSuf] {
+		suf++
+		idSuf = id + strconv.Itoa(suf)
+	}
+
+	c.operationIDs[idSuf] = operation
+
+	oc.SetID(idSuf)
+
+	return nil
 }
 
 func (c *Collector) setOCJSONResponse(oc openapi.OperationContext, output interface{}, statusCode int) {
@@ -430,13 +562,21 @@ func (c *Collector) processOCExpectedErrors(oc openapi.OperationContext, u useca
 		errsByCode        = map[int][]interface{}{}
 		statusCodes       []int
 		hasExpectedErrors usecase.HasExpectedErrors
+		errs              []error
+		timeoutErr        = -1
 	)
 
-	if !usecase.As(u, &hasExpectedErrors) {
-		return
+	if usecase.As(u, &hasExpectedErrors) {
+		errs = hasExpectedErrors.ExpectedErrors()
+	}
+
+	// Interaction that exceeds time limit fails with 504 Gateway Timeout.
+	if useCaseTimeout(u, h) > 0 {
+		errs = append(errs[:len(errs):len(errs)], status.DeadlineExceeded)
+		timeoutErr = len(errs) - 1
 	}
 
-	for _, e := range hasExpectedErrors.ExpectedErrors() {
+	for i, e := range errs {
 		var (
 			errResp     interface{}
 			statusCode  int
@@ -454,6 +594,10 @@ func (c *Collector) processOCExpectedErrors(oc openapi.OperationContext, u useca
 			statusCode, errResp = rest.Err(e)
 		}
 
+		if i == timeoutErr && errsByCode[statusCode] != nil {
+			continue
+		}
+
 		if statusCode < http.StatusOK || statusCode == http.StatusNotModified || statusCode == http.StatusNoContent {
 			errResp = nil
 		}
@@ -512,7 +656,7 @@ func (c *Collector) ProvideRequestJSONSchemas(
 	cu.Structure = input
 	setFieldMapping(&cu, mapping)
 
-	r := c.Refl()
+	r := c.refl()
 
 	err := r.WalkRequestJSONSchemas(method, cu, c.jsonSchemaCallback(validator, r), func(oc openapi.OperationContext) {
 		fv, ok := validator.(unknownFieldsValidator)
@@ -526,8 +670,11 @@ func (c *Collector) ProvideRequestJSONSchemas(
 			}
 		}
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return c.provideJSONBodySchema(input, validator)
 }
 
 // ProvideResponseJSONSchemas provides JSON schemas for response structure.
@@ -548,7 +695,7 @@ func (c *Collector) ProvideResponseJSONSchemas(
 		cu.ContentType = c.DefaultSuccessResponseContentType
 	}
 
-	r := c.Refl()
+	r := c.refl()
 	err := r.WalkResponseJSONSchemas(cu, c.jsonSchemaCallback(validator, r), nil)
 
 	return err
@@ -582,19 +729,29 @@ func (c *Collector) jsonSchemaCallback(validator rest.JSONSchemaValidator, r ope
 	}
 }
 
-func (c *Collector) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// processTimeout documents time limit of use case interaction with x-timeout extension.
+func (c *Collector) processTimeout(oc openapi.OperationContext, u usecase.Interactor, h rest.HandlerTrait) {
+	timeout := useCaseTimeout(u, h)
+	if timeout <= 0 {
+		return
+	}
 
-	document, err := json.MarshalIndent(c.SpecSchema(), "", " ")
-	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	switch o := oc.(type) {
+	case openapi3.OperationExposer:
+		o.Operation().WithMapOfAnythingItem("x-timeout", timeout.String())
+	case openapi31.OperationExposer:
+		o.Operation().WithMapOfAnythingItem("x-timeout", timeout.String())
 	}
+}
 
-	rw.Header().Set("Content-Type", "application/json")
+// useCaseTimeout returns time limit of use case interaction, handler timeout takes precedence.
+func useCaseTimeout(u usecase.Interactor, h rest.HandlerTrait) time.Duration {
+	timeout := h.Timeout
 
-	_, err = rw.Write(document)
-	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	var withTimeout rest.WithTimeout
+	if timeout == 0 && usecase.As(u, &withTimeout) {
+		timeout = withTimeout.Timeout()
 	}
+
+	return timeout
 }
@@ -513,3 +513,141 @@ func TestCollector_Collect_head_no_response(t *testing.T) {
 	  }
 	}`, c.SpecSchema())
 }
+
+func TestCollector_Collect_deprecation(t *testing.T) {
+	c := openapi.Collector{}
+	u := usecase.IOInteractor{}
+
+	type resp struct {
+		Foo string `json:"foo"`
+	}
+
+	u.Output = new(resp)
+
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/foo", usecase.Wrap(u, rest.Deprecation{
+		Sunset: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		Link:   "/v2/foo",
+	}), rest.HandlerTrait{}))
+
+	assertjson.EqMarshal(t, `{
+	  "responses":{
+		"200":{
+		  "description":"OK",
+		  "headers":{
+			"Deprecation":{"style":"simple","description":"Deprecation of operation (RFC 9745).","schema":{"type":"string"}},
+			"Link":{"style":"simple","description":"Link to successor version of operation.","schema":{"type":"string"}},
+			"Sunset":{
+			  "style":"simple",
+			  "description":"Operation becomes unavailable after Tue, 01 Jan 2030 00:00:00 GMT (RFC 8594).",
+			  "schema":{"type":"string"}
+			}
+		  },
+		  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/OpenapiTestResp"}}}
+		}
+	  },
+	  "deprecated":true
+	}`, c.SpecSchema().(*openapi3.Spec).Paths.MapOfPathItemValues["/foo"].MapOfOperationValues["get"])
+}
+
+func TestCollector_Collect_timeout(t *testing.T) {
+	c := openapi.Collector{}
+	u := usecase.IOInteractor{}
+
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/foo", u, rest.HandlerTrait{
+		Timeout: 2 * time.Second,
+	}))
+
+	expected := `{
+	  "responses":{
+		"204":{"description":"No Content"},
+		"504":{
+		  "description":"Gateway Timeout",
+		  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/RestErrResponse"}}}
+		}
+	  },
+	  "x-timeout":"2s"
+	}`
+
+	assertjson.EqMarshal(t, expected,
+		c.SpecSchema().(*openapi3.Spec).Paths.MapOfPathItemValues["/foo"].MapOfOperationValues["get"])
+
+	// Timeout response is not duplicated by expected error.
+	u.SetExpectedErrors(status.DeadlineExceeded)
+
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/bar", u, rest.HandlerTrait{
+		Timeout: 2 * time.Second,
+	}))
+
+	assertjson.EqMarshal(t, expected,
+		c.SpecSchema().(*openapi3.Spec).Paths.MapOfPathItemValues["/bar"].MapOfOperationValues["get"])
+}
+
+func TestCollector_CollectUseCase_optional(t *testing.T) {
+	type userID [16]byte
+
+	type address struct {
+		City string `json:"city"`
+	}
+
+	type req struct {
+		ID    rest.Optional[userID] `query:"id"`
+		Limit rest.Optional[int]    `query:"limit" default:"10"`
+		Owner rest.Nullable[userID] `json:"owner"`
+	}
+
+	type resp struct {
+		Address rest.Optional[address]   `json:"address"`
+		Backup  rest.Nullable[address]   `json:"backup"`
+		Tags    rest.Nullable[[]userID]  `json:"tags"`
+		Other   rest.Optional[[]address] `json:"other"`
+	}
+
+	r := openapi3.NewReflector()
+	r.JSONSchemaReflector().AddTypeMapping(userID{}, jschema.Schema{
+		Type: &jschema.Type{SimpleTypes: ptr(jschema.String)}, Format: ptr("uuid"),
+	})
+
+	c := openapi.NewCollector(r)
+
+	// Reflector shared by collectors is configured once.
+	options := len(r.JSONSchemaReflector().DefaultOptions)
+	openapi.NewCollector(r)
+	assert.Len(t, r.JSONSchemaReflector().DefaultOptions, options)
+
+	u := usecase.NewInteractor(func(_ context.Context, _ req, _ *resp) error { return nil })
+	require.NoError(t, c.CollectUseCase(http.MethodPost, "/users", u, rest.HandlerTrait{}))
+
+	assertjson.EqMarshal(t, `{
+	  "summary":"<ignore-diff>","operationId":"<ignore-diff>",
+	  "parameters":[
+		{"name":"id","in":"query","schema":{"$ref":"#/components/schemas/OpenapiTestUserID"}},
+		{"name":"limit","in":"query","schema":{"type":"integer","default":10}}
+	  ],
+	  "requestBody":{
+		"content":{"application/json":{"schema":{"$ref":"#/components/schemas/OpenapiTestReq"}}}
+	  },
+	  "responses":{
+		"200":{
+		  "description":"OK",
+		  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/OpenapiTestResp"}}}
+		}
+	  }
+	}`, r.Spec.Paths.MapOfPathItemValues["/users"].MapOfOperationValues["post"])
+
+	assertjson.EqMarshal(t, `{
+	  "OpenapiTestAddress":{"type":"object","properties":{"city":{"type":"string"}}},
+	  "OpenapiTestReq":{
+		"type":"object","properties":{"owner":{"type":"string","format":"uuid","nullable":true}}
+	  },
+	  "OpenapiTestResp":{
+		"type":"object",
+		"properties":{
+		  "address":{"$ref":"#/components/schemas/OpenapiTestAddress"},
+		  "backup":{"type":"object","properties":{"city":{"type":"string"}},"nullable":true},
+		  "other":{"type":"array","items":{"$ref":"#/components/schemas/OpenapiTestAddress"},"nullable":true},
+		  "tags":{"type":"array","items":{"$ref":"#/components/schemas/OpenapiTestUserID"},"nullable":true}
+		}
+	  },
+	  "OpenapiTestUserID":{"type":"string","format":"uuid"}
+	}`, r.Spec.Components.Schemas.MapOfSchemaOrRefValues)
+}
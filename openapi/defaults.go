@@ -0,0 +1,182 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// GroupDefaults describes OpenAPI properties that are merged into every operation of a route group.
+//
+// Properties that are already defined by operation take precedence, defaults can be applied more than once
+// (for example with nested groups) without duplicating tags, parameters, responses or servers.
+type GroupDefaults struct {
+	// Tags are added to operation tags.
+	Tags []string
+
+	// Security maps security scheme names to required scopes,
+	// it is added as a single requirement (all schemes are required) if operation has no security requirements.
+	//
+	// Operation security is checked when defaults are applied, security that is added later (for example by
+	// security middleware of an outer router) is documented as an alternative requirement,
+	// so security middlewares and defaults with Security should be used in the same route group.
+	Security map[string][]string
+
+	// Responses maps HTTP status codes to sample response structures, for example rest.ErrResponse{}.
+	Responses map[int]interface{}
+
+	// Servers are URLs that override document servers for operations.
+	Servers []string
+
+	// Parameters is a sample structure of common parameters with `path`, `query`, `header` or `cookie` tags.
+	Parameters interface{}
+}
+
+// Annotate applies defaults to operation.
+func (d GroupDefaults) Annotate(oc openapi.OperationContext) error {
+	if len(d.Tags) > 0 {
+		tags := oc.Tags()
+		exists := make(map[string]bool, len(tags))
+
+		for _, t := range tags {
+			exists[t] = true
+		}
+
+		for _, t := range d.Tags {
+			if !exists[t] {
+				tags = append(tags, t)
+			}
+		}
+
+		oc.SetTags(tags...)
+	}
+
+	if len(d.Security) > 0 && !hasSecurity(oc) {
+		if err := d.addSecurity(oc); err != nil {
+			return err
+		}
+	}
+
+	if d.Parameters != nil && !d.hasParameters(oc) {
+		oc.AddReqStructure(d.Parameters)
+	}
+
+	d.addResponses(oc)
+
+	if len(d.Servers) > 0 {
+		return d.setServers(oc)
+	}
+
+	return nil
+}
+
+func hasSecurity(oc openapi.OperationContext) bool {
+	switch o := oc.(type) {
+	case openapi3.OperationExposer:
+		return len(o.Operation().Security) > 0
+	case openapi31.OperationExposer:
+		return len(o.Operation().Security) > 0
+	default:
+		return false
+	}
+}
+
+func (d GroupDefaults) addSecurity(oc openapi.OperationContext) error {
+	req := make(map[string][]string, len(d.Security))
+
+	for name, scopes := range d.Security {
+		req[name] = append([]string{}, scopes...)
+	}
+
+	switch o := oc.(type) {
+	case openapi3.OperationExposer:
+		o.Operation().Security = append(o.Operation().Security, req)
+	case openapi31.OperationExposer:
+		o.Operation().Security = append(o.Operation().Security, req)
+	default:
+		return fmt.Errorf("operation security is not supported for %T", oc)
+	}
+
+	return nil
+}
+
+func (d GroupDefaults) hasParameters(oc openapi.OperationContext) bool {
+	for _, cu := range oc.Request() {
+		if reflect.DeepEqual(cu.Structure, d.Parameters) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d GroupDefaults) addResponses(oc openapi.OperationContext) {
+	statuses := make([]int, 0, len(d.Responses))
+
+	for status := range d.Responses {
+		statuses = append(statuses, status)
+	}
+
+	sort.Ints(statuses)
+
+	defined := map[int]bool{}
+
+	for _, cu := range oc.Response() {
+		status := cu.HTTPStatus
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		defined[status] = true
+	}
+
+	for _, status := range statuses {
+		if defined[status] {
+			continue
+		}
+
+		oc.AddRespStructure(d.Responses[status], openapi.WithHTTPStatus(status))
+	}
+}
+
+func (d GroupDefaults) setServers(oc openapi.OperationContext) error {
+	switch o := oc.(type) {
+	case openapi3.OperationExposer:
+		op := o.Operation()
+
+		exists := make(map[string]bool, len(op.Servers))
+		for _, s := range op.Servers {
+			exists[s.URL] = true
+		}
+
+		for _, s := range d.Servers {
+			if !exists[s] {
+				exists[s] = true
+				op.Servers = append(op.Servers, openapi3.Server{URL: s})
+			}
+		}
+	case openapi31.OperationExposer:
+		op := o.Operation()
+
+		exists := make(map[string]bool, len(op.Servers))
+		for _, s := range op.Servers {
+			exists[s.URL] = true
+		}
+
+		for _, s := range d.Servers {
+			if !exists[s] {
+				exists[s] = true
+				op.Servers = append(op.Servers, openapi31.Server{URL: s})
+			}
+		}
+	default:
+		return fmt.Errorf("operation servers are not supported for %T", oc)
+	}
+
+	return nil
+}
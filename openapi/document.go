@@ -0,0 +1,366 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Document formats.
+const (
+	formatJSON        = "json"
+	formatCompactJSON = "compact"
+	formatYAML        = "yaml"
+)
+
+// maxDocuments limits number of cached documents with rewritten server URL.
+const maxDocuments = 100
+
+// forwardedHeaders are used to build server URL of request from a trusted proxy.
+var forwardedHeaders = []string{"X-Forwarded-Proto", "X-Forwarded-Host", "X-Forwarded-Prefix"}
+
+type document struct {
+	contentType string
+	data        []byte
+	etag        string
+}
+
+// InvalidateCache discards serialized documents.
+//
+// Documents are invalidated automatically when operations or webhooks are collected and when spec
+// or reflector is accessed with SpecSchema, Refl or Reflector, as they may be used to change the spec.
+func (c *Collector) InvalidateCache() {
+	c.docMu.Lock()
+	defer c.docMu.Unlock()
+
+	c.docGen++
+	c.documents = nil
+	c.serverDocuments = nil
+}
+
+// ServeHTTP serves OpenAPI document.
+//
+// Document format is negotiated with "format" query parameter ("json", "compact" or "yaml"),
+// ".yaml" or ".yml" URL path suffix, or with Accept header. Indented JSON is served by default.
+// Serialized documents are cached, ETag and If-None-Match are supported.
+func (c *Collector) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	format := documentFormat(r)
+
+	serverURL := ""
+	if c.ServerURLFromRequest && r != nil {
+		serverURL = requestServerURL(r, c.trustedProxy(r.RemoteAddr))
+	}
+
+	doc, err := c.document(format, serverURL)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", doc.contentType)
+	rw.Header().Set("Etag", doc.etag)
+	rw.Header().Add("Vary", "Accept")
+
+	if c.ServerURLFromRequest {
+		rw.Header().Add("Vary", "Host")
+
+		if len(c.TrustedProxies) > 0 {
+			rw.Header().Add("Vary", strings.Join(forwardedHeaders, ", "))
+		}
+	}
+
+	if r != nil && etagMatches(r.Header.Get("If-None-Match"), doc.etag) {
+		rw.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	_, err = rw.Write(doc.data)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// CompactJSON returns cached compact JSON document of spec.
+//
+// Unlike marshaling SpecSchema, it is safe to call concurrently with collection and serving.
+func (c *Collector) CompactJSON() ([]byte, error) {
+	doc, err := c.document(formatCompactJSON, "")
+
+	return doc.data, err
+}
+
+func (c *Collector) document(format, serverURL string) (document, error) {
+	if serverURL != "" {
+		return c.serverDocument(format, serverURL)
+	}
+
+	c.docMu.Lock()
+	doc, ok := c.documents[format]
+	gen := c.docGen
+	c.docMu.Unlock()
+
+	if ok {
+		return doc, nil
+	}
+
+	c.mu.Lock()
+	doc, err := marshalDocument(c.refl().SpecSchema(), format)
+	c.mu.Unlock()
+
+	if err != nil {
+		return doc, err
+	}
+
+	c.docMu.Lock()
+	defer c.docMu.Unlock()
+
+	// Document is not cached if spec was changed during marshaling.
+	if gen == c.docGen {
+		if c.documents == nil {
+			c.documents = make(map[string]document)
+		}
+
+		c.documents[format] = doc
+	}
+
+	return doc, nil
+}
+
+// serverDocument renders document with replaced servers from a private copy of spec without locking collector.
+func (c *Collector) serverDocument(format, serverURL string) (document, error) {
+	key := format + " " + serverURL
+
+	c.docMu.Lock()
+	doc, ok := c.serverDocuments[key]
+	gen := c.docGen
+	c.docMu.Unlock()
+
+	if ok {
+		return doc, nil
+	}
+
+	base, err := c.document(formatCompactJSON, "")
+	if err != nil {
+		return doc, err
+	}
+
+	c.mu.Lock()
+	t := reflect.TypeOf(c.refl().SpecSchema())
+	c.mu.Unlock()
+
+	spec, ok := reflect.New(t.Elem()).Interface().(openapi.SpecSchema)
+	if !ok || t.Kind() != reflect.Ptr {
+		return doc, fmt.Errorf("unexpected spec type %s", t.String())
+	}
+
+	if err := json.Unmarshal(base.data, spec); err != nil {
+		return doc, err
+	}
+
+	setServerURL(spec, serverURL)
+
+	doc, err = marshalDocument(spec, format)
+	if err != nil {
+		return doc, err
+	}
+
+	c.docMu.Lock()
+	defer c.docMu.Unlock()
+
+	if gen == c.docGen {
+		if c.serverDocuments == nil {
+			c.serverDocuments = make(map[string]document)
+		}
+
+		// Arbitrary entry is evicted to keep cache bounded.
+		if len(c.serverDocuments) >= maxDocuments {
+			for k := range c.serverDocuments {
+				delete(c.serverDocuments, k)
+
+				break
+			}
+		}
+
+		c.serverDocuments[key] = doc
+	}
+
+	return doc, nil
+}
+
+func marshalDocument(spec openapi.SpecSchema, format string) (document, error) {
+	var (
+		doc = document{contentType: "application/json"}
+		err error
+	)
+
+	switch format {
+	case formatCompactJSON:
+		doc.data, err = json.Marshal(spec)
+	case formatYAML:
+		doc.contentType = "application/yaml"
+
+		if y, ok := spec.(interface{ MarshalYAML() ([]byte, error) }); ok {
+			doc.data, err = y.MarshalYAML()
+		} else {
+			doc.contentType = "application/json"
+			doc.data, err = json.MarshalIndent(spec, "", " ")
+		}
+	default:
+		doc.data, err = json.MarshalIndent(spec, "", " ")
+	}
+
+	if err != nil {
+		return doc, err
+	}
+
+	doc.etag = `"` + strconv.FormatUint(xxhash.Sum64(doc.data), 36) + `"`
+
+	return doc, nil
+}
+
+// setServerURL replaces servers of spec.
+//
+// Path of the first configured server is appended to serverURL.
+func setServerURL(spec openapi.SpecSchema, serverURL string) {
+	switch s := spec.(type) {
+	case *openapi3.Spec:
+		if len(s.Servers) > 0 {
+			serverURL += serverPath(s.Servers[0].URL)
+		}
+
+		s.Servers = []openapi3.Server{{URL: serverURL}}
+	case *openapi31.Spec:
+		if len(s.Servers) > 0 {
+			serverURL += serverPath(s.Servers[0].URL)
+		}
+
+		s.Servers = []openapi31.Server{{URL: serverURL}}
+	}
+}
+
+// trustedProxy checks if remote address matches TrustedProxies.
+func (c *Collector) trustedProxy(remoteAddr string) bool {
+	if len(c.TrustedProxies) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, p := range c.TrustedProxies {
+		if strings.Contains(p, "/") {
+			if _, n, err := net.ParseCIDR(p); err == nil && n.Contains(ip) {
+				return true
+			}
+		} else if pip := net.ParseIP(p); pip != nil && pip.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func serverPath(serverURL string) string {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimRight(u.Path, "/")
+}
+
+// requestServerURL returns base URL of service as seen by client.
+//
+// X-Forwarded-* headers are only used if request is received from a trusted proxy.
+func requestServerURL(r *http.Request, trusted bool) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	if !trusted {
+		return scheme + "://" + r.Host
+	}
+
+	if p := firstValue(r.Header.Get("X-Forwarded-Proto")); p == "http" || p == "https" {
+		scheme = p
+	}
+
+	host := r.Host
+	if h := firstValue(r.Header.Get("X-Forwarded-Host")); h != "" {
+		host = h
+	}
+
+	prefix := strings.TrimRight(firstValue(r.Header.Get("X-Forwarded-Prefix")), "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+
+	return scheme + "://" + host + prefix
+}
+
+func firstValue(h string) string {
+	if i := strings.Index(h, ","); i >= 0 {
+		h = h[:i]
+	}
+
+	return strings.TrimSpace(h)
+}
+
+func documentFormat(r *http.Request) string {
+	if r == nil {
+		return formatJSON
+	}
+
+	switch f := r.URL.Query().Get("format"); f {
+	case formatJSON, formatCompactJSON, formatYAML:
+		return f
+	case "yml":
+		return formatYAML
+	}
+
+	if strings.HasSuffix(r.URL.Path, ".yaml") || strings.HasSuffix(r.URL.Path, ".yml") {
+		return formatYAML
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "yaml") {
+		return formatYAML
+	}
+
+	return formatJSON
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimSpace(t)
+
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package openapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/usecase"
+)
+
+func TestCollector_ServeHTTP_formats(t *testing.T) {
+	c := openapi.NewCollector(openapi3.NewReflector())
+	c.ServerURLFromRequest = true
+	c.TrustedProxies = []string{"10.0.0.1", "192.0.2.0/24"}
+	c.SpecSchema().SetTitle("Sample")
+	c.Reflector().Spec.WithServers(openapi3.Server{URL: "/api/"})
+
+	remoteAddr := "192.0.2.1:1234"
+
+	serve := func(target string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.RemoteAddr = remoteAddr
+
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+
+		rw := httptest.NewRecorder()
+		c.ServeHTTP(rw, req)
+
+		return rw
+	}
+
+	rw := serve("http://example.com/docs/openapi.json?format=compact", nil)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+	assert.Equal(t, `{"openapi":"3.0.3","info":{"title":"Sample","version":""},`+
+		`"servers":[{"url":"http://example.com/api"}],"paths":{}}`, rw.Body.String())
+	assert.Equal(t, []string{"Accept", "Host", "X-Forwarded-Proto, X-Forwarded-Host, X-Forwarded-Prefix"},
+		rw.Header().Values("Vary"))
+
+	etag := rw.Header().Get("Etag")
+	require.NotEmpty(t, etag)
+
+	rw = serve("http://example.com/docs/openapi.json?format=compact", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+	assert.Empty(t, rw.Body.String())
+
+	rw = serve("http://internal/docs/openapi.yaml", map[string]string{
+		"X-Forwarded-Proto":  "https",
+		"X-Forwarded-Host":   "api.example.com, proxy",
+		"X-Forwarded-Prefix": "/v1",
+	})
+	assert.Equal(t, "application/yaml", rw.Header().Get("Content-Type"))
+	assert.Equal(t, `openapi: 3.0.3
+info:
+  title: Sample
+  version: ""
+servers:
+- url: https://api.example.com/v1/api
+paths: {}
+`, rw.Body.String())
+
+	// Forwarded headers are ignored from untrusted address.
+	remoteAddr = "203.0.113.1:1234"
+	rw = serve("http://internal/docs/openapi.json?format=compact", map[string]string{
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "api.example.com",
+	})
+	assert.Equal(t, `{"openapi":"3.0.3","info":{"title":"Sample","version":""},`+
+		`"servers":[{"url":"http://internal/api"}],"paths":{}}`, rw.Body.String())
+
+	remoteAddr = "10.0.0.1:1234"
+
+	// Original servers are preserved in spec.
+	assert.Equal(t, "/api/", c.Reflector().Spec.Servers[0].URL)
+
+	u := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error { return nil })
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/foo", u, rest.HandlerTrait{}))
+
+	rw = serve("http://example.com/docs/openapi.json?format=compact", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.NotEqual(t, etag, rw.Header().Get("Etag"))
+	assertjson.Equal(t, []byte(`{
+	  "openapi":"3.0.3","info":{"title":"Sample","version":""},
+	  "servers":[{"url":"http://example.com/api"}],
+	  "paths":{"/foo":{"get":{"summary":"<ignore-diff>","operationId":"<ignore-diff>","responses":"<ignore-diff>"}}}
+	}`), rw.Body.Bytes())
+}
+
+func TestCollector_ServeHTTP_invalidate(t *testing.T) {
+	c := openapi.NewCollector(openapi3.NewReflector())
+	c.SpecSchema().SetTitle("Sample")
+
+	serve := func() string {
+		rw := httptest.NewRecorder()
+		c.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/docs/openapi.json?format=compact", nil))
+
+		return rw.Body.String()
+	}
+
+	assert.Equal(t, `{"openapi":"3.0.3","info":{"title":"Sample","version":""},"paths":{}}`, serve())
+
+	// Direct change of spec is served.
+	c.SpecSchema().SetTitle("Changed")
+	assert.Equal(t, `{"openapi":"3.0.3","info":{"title":"Changed","version":""},"paths":{}}`, serve())
+
+	c.Reflector().Spec.Info.WithVersion("v1")
+	assert.Equal(t, `{"openapi":"3.0.3","info":{"title":"Changed","version":"v1"},"paths":{}}`, serve())
+
+	data, err := c.CompactJSON()
+	require.NoError(t, err)
+	assert.Equal(t, serve(), string(data))
+}
@@ -0,0 +1,40 @@
+package openapi
+
+import (
+	"sync"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// exclusiveBoundReflectors keeps OpenAPI 3.0 reflectors that have exclusive bound interceptor.
+var exclusiveBoundReflectors sync.Map
+
+// interceptExclusiveBounds keeps value of numeric exclusiveMinimum/exclusiveMaximum in minimum/maximum,
+// so that OpenAPI 3.0 schema with boolean exclusiveMinimum/exclusiveMaximum has the bound.
+func interceptExclusiveBounds(r *openapi3.Reflector) {
+	jr := r.JSONSchemaReflector()
+
+	if _, loaded := exclusiveBoundReflectors.LoadOrStore(jr, true); loaded {
+		return
+	}
+
+	jr.DefaultOptions = append(jr.DefaultOptions, jsonschema.InterceptProp(
+		func(params jsonschema.InterceptPropParams) error {
+			if !params.Processed {
+				return nil
+			}
+
+			s := params.PropertySchema
+
+			if s.ExclusiveMinimum != nil && s.Minimum == nil {
+				s.Minimum = s.ExclusiveMinimum
+			}
+
+			if s.ExclusiveMaximum != nil && s.Maximum == nil {
+				s.Maximum = s.ExclusiveMaximum
+			}
+
+			return nil
+		}))
+}
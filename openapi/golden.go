@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/rest/diff"
+)
+
+// UpdateGoldenEnv is a name of environment variable that enables rewriting of golden files by AssertGolden.
+var UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// MarshalSpec renders spec as indented JSON with sorted keys.
+func MarshalSpec(spec openapi.SpecSchema) ([]byte, error) {
+	j, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.UseNumber()
+
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	j, err = json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(j, '\n'), nil
+}
+
+// AssertGolden checks that spec matches golden file and reports unified diff if it does not.
+//
+// Golden file is rewritten with current spec if environment variable UpdateGoldenEnv is set,
+// for example with "UPDATE_GOLDEN=1 go test ./...".
+func AssertGolden(t diff.TestingT, goldenFile string, spec openapi.SpecSchema) bool {
+	t.Helper()
+
+	actual, err := MarshalSpec(spec)
+	if err != nil {
+		t.Errorf("marshal spec: %v", err)
+
+		return false
+	}
+
+	if v := os.Getenv(UpdateGoldenEnv); v != "" && v != "0" && v != "false" {
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0o750); err != nil {
+			t.Errorf("create golden file directory: %v", err)
+
+			return false
+		}
+
+		if err := os.WriteFile(goldenFile, actual, 0o600); err != nil {
+			t.Errorf("write golden file: %v", err)
+
+			return false
+		}
+
+		return true
+	}
+
+	expected, err := os.ReadFile(goldenFile) //nolint:gosec // Path is provided by test.
+	if err != nil {
+		t.Errorf("read golden file (set %s=1 to create it): %v", UpdateGoldenEnv, err)
+
+		return false
+	}
+
+	if bytes.Equal(expected, actual) {
+		return true
+	}
+
+	ud, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expected)),
+		B:        difflib.SplitLines(string(actual)),
+		FromFile: goldenFile,
+		ToFile:   "actual",
+		Context:  3,
+	})
+	if err != nil {
+		t.Errorf("spec does not match %s: %v", goldenFile, err)
+
+		return false
+	}
+
+	t.Errorf("spec does not match %s (set %s=1 to update):\n%s", goldenFile, UpdateGoldenEnv, ud)
+
+	return false
+}
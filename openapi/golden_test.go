@@ -0,0 +1,71 @@
+package openapi_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/openapi"
+)
+
+type mockT struct {
+	err string
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Errorf(format string, args ...interface{}) {
+	m.err += fmt.Sprintf(format, args...)
+}
+
+func TestAssertGolden(t *testing.T) {
+	t.Setenv(openapi.UpdateGoldenEnv, "")
+
+	golden := filepath.Join(t.TempDir(), "testdata", "openapi.json")
+
+	r := openapi3.NewReflector()
+	r.Spec.Info.WithTitle("Sample").WithVersion("v1")
+
+	mt := &mockT{}
+	assert.False(t, openapi.AssertGolden(mt, golden, r.Spec))
+	assert.Contains(t, mt.err, "read golden file (set UPDATE_GOLDEN=1 to create it)")
+
+	t.Setenv(openapi.UpdateGoldenEnv, "1")
+	assert.True(t, openapi.AssertGolden(t, golden, r.Spec))
+
+	data, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "info": {
+    "title": "Sample",
+    "version": "v1"
+  },
+  "openapi": "3.0.3",
+  "paths": {}
+}
+`, string(data))
+
+	t.Setenv(openapi.UpdateGoldenEnv, "")
+	assert.True(t, openapi.AssertGolden(t, golden, r.Spec))
+
+	r.Spec.Info.WithVersion("v2")
+
+	mt = &mockT{}
+	assert.False(t, openapi.AssertGolden(mt, golden, r.Spec))
+	assert.Equal(t, "spec does not match "+golden+" (set UPDATE_GOLDEN=1 to update):\n"+
+		"--- "+golden+"\n"+
+		"+++ actual\n"+
+		"@@ -1,7 +1,7 @@\n"+
+		" {\n"+
+		"   \"info\": {\n"+
+		"     \"title\": \"Sample\",\n"+
+		"-    \"version\": \"v1\"\n"+
+		"+    \"version\": \"v2\"\n"+
+		"   },\n"+
+		"   \"openapi\": \"3.0.3\",\n"+
+		"   \"paths\": {}\n", mt.err)
+}
@@ -0,0 +1,96 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/rest"
+)
+
+// responseHeader is a documented response header that is not reflected from output structure.
+type responseHeader struct {
+	name        string
+	description string
+}
+
+// withResponseHeaders returns content unit customization to add headers to response.
+//
+// Previously configured customization is preserved and invoked first.
+func withResponseHeaders(prev func(cor openapi.ContentOrReference), headers ...responseHeader) func(cor openapi.ContentOrReference) {
+	return func(cor openapi.ContentOrReference) {
+		if prev != nil {
+			prev(cor)
+		}
+
+		for _, h := range headers {
+			setResponseHeader(cor, h.name, h.description)
+		}
+	}
+}
+
+func setResponseHeader(cor openapi.ContentOrReference, name, description string) {
+	switch r := cor.(type) {
+	case *openapi3.ResponseOrRef:
+		if r.Response == nil {
+			return
+		}
+
+		if r.Response.Headers == nil {
+			r.Response.Headers = make(map[string]openapi3.HeaderOrRef)
+		}
+
+		h := openapi3.Header{}
+		h.Schema = &openapi3.SchemaOrRef{Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeString)}
+
+		if description != "" {
+			h.WithDescription(description)
+		}
+
+		r.Response.Headers[name] = openapi3.HeaderOrRef{Header: &h}
+	case *openapi31.ResponseOrReference:
+		if r.Response == nil {
+			return
+		}
+
+		if r.Response.Headers == nil {
+			r.Response.Headers = make(map[string]openapi31.HeaderOrReference)
+		}
+
+		h := openapi31.Header{}
+		h.Schema = map[string]interface{}{"type": "string"}
+
+		if description != "" {
+			h.WithDescription(description)
+		}
+
+		r.Response.Headers[name] = openapi31.HeaderOrReference{Header: &h}
+	}
+}
+
+// WithResponseHeader is a ContentUnit option to document response header that is not reflected from
+// output structure, e.g. "Allow".
+func WithResponseHeader(name, description string) openapi.ContentOption {
+	return func(cu *openapi.ContentUnit) {
+		cu.Customize = withResponseHeaders(cu.Customize, responseHeader{name: name, description: description})
+	}
+}
+
+// deprecationHeaders returns documentation of headers emitted for deprecated use case.
+func deprecationHeaders(d rest.Deprecation, hasLink bool) []responseHeader {
+	headers := []responseHeader{{name: "Deprecation", description: "Deprecation of operation (RFC 9745)."}}
+
+	if !d.Sunset.IsZero() {
+		headers = append(headers, responseHeader{
+			name:        "Sunset",
+			description: "Operation becomes unavailable after " + d.Sunset.UTC().Format(http.TimeFormat) + " (RFC 8594).",
+		})
+	}
+
+	if d.Link != "" && !hasLink {
+		headers = append(headers, responseHeader{name: "Link", description: "Link to successor version of operation."})
+	}
+
+	return headers
+}
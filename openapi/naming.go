@@ -0,0 +1,189 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/swaggest/usecase"
+)
+
+// OperationNaming contains inferred operation ID, summary and tags.
+type OperationNaming struct {
+	ID      string
+	Summary string
+	Tags    []string
+}
+
+// NamingStrategy infers operation ID, summary and tags.
+type NamingStrategy interface {
+	OperationNaming(method, pattern string, u usecase.Interactor) OperationNaming
+}
+
+// NamingStrategyFunc implements NamingStrategy with a function.
+type NamingStrategyFunc func(method, pattern string, u usecase.Interactor) OperationNaming
+
+// OperationNaming implements NamingStrategy.
+func (f NamingStrategyFunc) OperationNaming(method, pattern string, u usecase.Interactor) OperationNaming {
+	return f(method, pattern, u)
+}
+
+// ChainNaming combines strategies, first non-empty value of each property is used.
+func ChainNaming(strategies ...NamingStrategy) NamingStrategy {
+	return NamingStrategyFunc(func(method, pattern string, u usecase.Interactor) OperationNaming {
+		res := OperationNaming{}
+
+		for _, s := range strategies {
+			n := s.OperationNaming(method, pattern, u)
+
+			if res.ID == "" {
+				res.ID = n.ID
+			}
+
+			if res.Summary == "" {
+				res.Summary = n.Summary
+			}
+
+			if len(res.Tags) == 0 {
+				res.Tags = n.Tags
+			}
+		}
+
+		return res
+	})
+}
+
+// MethodPatternNaming derives naming from HTTP method and URL pattern.
+//
+// For example, "GET /users/{id}/posts" gets ID "getUsersByIdPosts", summary "Get users by id posts"
+// and tag "users". Operations are tagged with first static segment of pattern after Prefix,
+// that usually corresponds to top-level chi Route group.
+//
+// Route groups are not known to collector, so nested groups (for example, "/admin" group with "/users" group
+// inside) are not reflected in tags: both "/admin/users" and "/admin/settings" are tagged "admin".
+// Use Prefix to skip common leading segments, or tag operations of a group with openapi.GroupDefaults.
+type MethodPatternNaming struct {
+	// Prefix is trimmed from pattern, for example "/api/v1".
+	Prefix string
+}
+
+// OperationNaming implements NamingStrategy.
+func (m MethodPatternNaming) OperationNaming(method, pattern string, _ usecase.Interactor) OperationNaming {
+	pattern = strings.TrimPrefix(pattern, strings.TrimRight(m.Prefix, "/"))
+
+	words := []string{strings.ToLower(method)}
+	res := OperationNaming{}
+
+	for _, seg := range strings.Split(pattern, "/") {
+		if seg == "" {
+			continue
+		}
+
+		if strings.HasPrefix(seg, "{") {
+			// Parameter name without chi regexp.
+			seg = strings.Trim(strings.SplitN(seg, ":", 2)[0], "{}")
+			words = append(words, "by")
+		} else if len(res.Tags) == 0 {
+			res.Tags = []string{seg}
+		}
+
+		words = append(words, splitWords(seg)...)
+	}
+
+	for i, w := range words {
+		w = strings.ToLower(w)
+
+		if i == 0 {
+			res.ID = w
+			res.Summary = upperFirst(w)
+
+			continue
+		}
+
+		res.ID += upperFirst(w)
+		res.Summary += " " + w
+	}
+
+	return res
+}
+
+// TypeNaming derives naming from Go type of use case interactor.
+//
+// For example, interactor of type *users.CreateUser gets ID "users.CreateUser", summary "Create user"
+// and tag "users". Use cases made with usecase.NewInteractor have generic type and are skipped.
+type TypeNaming struct{}
+
+// OperationNaming implements NamingStrategy.
+func (TypeNaming) OperationNaming(_, _ string, u usecase.Interactor) OperationNaming {
+	t := reflect.TypeOf(u)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Name() == "" || strings.Contains(t.Name(), "[") || t.PkgPath() == "github.com/swaggest/usecase" {
+		return OperationNaming{}
+	}
+
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+
+	words := splitWords(t.Name())
+	for i := range words {
+		words[i] = strings.ToLower(words[i])
+	}
+
+	return OperationNaming{
+		ID:      pkg + "." + t.Name(),
+		Summary: upperFirst(strings.Join(words, " ")),
+		Tags:    []string{pkg},
+	}
+}
+
+// splitWords splits identifier or URL segment into words.
+func splitWords(s string) []string {
+	var (
+		words []string
+		cur   []rune
+	)
+
+	runes := []rune(s)
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(cur) > 0 {
+				words = append(words, string(cur))
+				cur = nil
+			}
+
+			continue
+		}
+
+		// Word boundary on lower-to-upper transition or before last upper letter of acronym.
+		if len(cur) > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			words = append(words, string(cur))
+			cur = nil
+		}
+
+		cur = append(cur, r)
+	}
+
+	if len(cur) > 0 {
+		words = append(words, string(cur))
+	}
+
+	return words
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+
+	return string(r)
+}
@@ -0,0 +1,115 @@
+package openapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/usecase"
+)
+
+type createUserHTTP struct{}
+
+func (createUserHTTP) Interact(_ context.Context, _, _ interface{}) error {
+	return nil
+}
+
+func TestMethodPatternNaming_OperationNaming(t *testing.T) {
+	n := openapi.MethodPatternNaming{Prefix: "/api/v1/"}
+
+	assert.Equal(t, openapi.OperationNaming{
+		ID:      "getUsersByIdPosts",
+		Summary: "Get users by id posts",
+		Tags:    []string{"users"},
+	}, n.OperationNaming(http.MethodGet, "/api/v1/users/{id:[0-9]+}/posts", nil))
+
+	assert.Equal(t, openapi.OperationNaming{
+		ID:      "postUserProfilesByUserId",
+		Summary: "Post user profiles by user id",
+		Tags:    []string{"user-profiles"},
+	}, n.OperationNaming(http.MethodPost, "/api/v1/user-profiles/{userID}", nil))
+}
+
+func TestTypeNaming_OperationNaming(t *testing.T) {
+	assert.Equal(t, openapi.OperationNaming{
+		ID:      "openapi_test.createUserHTTP",
+		Summary: "Create user http",
+		Tags:    []string{"openapi_test"},
+	}, openapi.TypeNaming{}.OperationNaming(http.MethodPost, "/users", &createUserHTTP{}))
+
+	u := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error { return nil })
+	assert.Equal(t, openapi.OperationNaming{}, openapi.TypeNaming{}.OperationNaming(http.MethodPost, "/users", u))
+}
+
+func TestCollector_NamingStrategy(t *testing.T) {
+	c := openapi.NewCollector(openapi3.NewReflector())
+	c.NamingStrategy = openapi.ChainNaming(openapi.TypeNaming{}, openapi.MethodPatternNaming{})
+	c.StrictOperationIDs = true
+
+	named := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error { return nil })
+	named.SetName("listUsers")
+	named.SetTitle("")
+
+	require.NoError(t, c.CollectUseCase(http.MethodPost, "/users", createUserHTTP{}, rest.HandlerTrait{}))
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/users", named, rest.HandlerTrait{}))
+
+	// Same use case on another method of the same pattern gets method suffix.
+	require.NoError(t, c.CollectUseCase(http.MethodPut, "/users", createUserHTTP{}, rest.HandlerTrait{}))
+
+	err := c.CollectUseCase(http.MethodPut, "/people", createUserHTTP{}, rest.HandlerTrait{})
+	assert.EqualError(t, err, "reflect API schema for PUT /people: "+
+		`duplicate operation ID "openapi_test.createUserHTTP", already used by POST /users`)
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/users":{
+		  "get":{
+			"tags":["users"],"summary":"Get users","operationId":"listUsers",
+			"responses":{"204":{"description":"No Content"}}
+		  },
+		  "post":{
+			"tags":["openapi_test"],"summary":"Create user http","operationId":"openapi_test.createUserHTTP",
+			"responses":{"204":{"description":"No Content"}}
+		  },
+		  "put":{
+			"tags":["openapi_test"],"summary":"Create user http","operationId":"openapi_test.createUserHTTPPut",
+			"responses":{"204":{"description":"No Content"}}
+		  }
+		}
+	  }
+	}`, c.SpecSchema())
+
+	c = openapi.NewCollector(openapi3.NewReflector())
+	c.NamingStrategy = openapi.MethodPatternNaming{}
+	c.PreferNamingStrategy = true
+
+	type userByID struct {
+		ID int `path:"id"`
+	}
+
+	byID := usecase.NewInteractor(func(_ context.Context, _ userByID, _ *struct{}) error { return nil })
+	byID.SetName("getUser")
+
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/users", named, rest.HandlerTrait{}))
+	require.NoError(t, c.CollectUseCase(http.MethodGet, "/users/{id}", byID, rest.HandlerTrait{}))
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/users":{"get":{"tags":["users"],"summary":"Get users","operationId":"getUsers","responses":"<ignore-diff>"}},
+		"/users/{id}":{
+		  "get":{
+			"tags":["users"],"summary":"Get users by id","operationId":"getUsersById",
+			"parameters":"<ignore-diff>","responses":"<ignore-diff>"
+		  }
+		}
+	  }
+	}`, c.SpecSchema())
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/rest"
+)
+
+// interceptedReflectors keeps JSON schema reflectors that have value schema interceptor,
+// so that reflector shared by multiple collectors is configured once.
+var interceptedReflectors sync.Map
+
+// interceptValueSchemas makes reflector use itself to reflect values of rest.Optional and rest.Nullable,
+// so that type mappings, interceptors and definitions of reflector apply to underlying values.
+func interceptValueSchemas(r openapi.Reflector) {
+	jr := r.JSONSchemaReflector()
+
+	if _, loaded := interceptedReflectors.LoadOrStore(jr, true); loaded {
+		return
+	}
+
+	jr.DefaultOptions = append(jr.DefaultOptions, jsonschema.InterceptSchema(
+		func(params jsonschema.InterceptSchemaParams) (bool, error) {
+			if params.Processed || !params.Value.IsValid() {
+				return false, nil
+			}
+
+			t := params.Value.Type()
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+
+			e, ok := reflect.Zero(t).Interface().(rest.ValueSchemaExposer)
+			if !ok {
+				return false, nil
+			}
+
+			sample, nullable := e.JSONSchemaValue()
+			rc := params.Context
+
+			vs, err := jr.Reflect(sample, func(vrc *jsonschema.ReflectContext) {
+				vrc.Context = rc.Context
+				vrc.DefName = rc.DefName
+				vrc.CollectDefinitions = rc.CollectDefinitions
+				vrc.DefinitionsPrefix = rc.DefinitionsPrefix
+				vrc.EnvelopNullability = rc.EnvelopNullability
+
+				// Definitions can only be shared with parent schema by collecting them.
+				vrc.InlineRefs = rc.InlineRefs || rc.CollectDefinitions == nil
+				vrc.RootRef = !vrc.InlineRefs && !nullable
+			})
+			if err != nil {
+				return true, err
+			}
+
+			vs.ReflectType = params.Schema.ReflectType
+			vs.Parent = params.Schema.Parent
+
+			if nullable && vs.Type != nil {
+				vs.AddType(jsonschema.Null)
+			}
+
+			*params.Schema = vs
+
+			return true, nil
+		}))
+}
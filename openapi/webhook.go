@@ -0,0 +1,135 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/rest/webhook"
+)
+
+// callbackPath is a temporary path to reflect callback operation.
+const callbackPath = "/.callback"
+
+// AddWebhook adds webhook to documentation, it requires OpenAPI 3.1 reflector.
+func (c *Collector) AddWebhook(w webhook.Webhook) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.InvalidateCache()
+
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("reflect webhook %s: %w", w.Name, err)
+		}
+	}()
+
+	r31, ok := c.refl().(*openapi31.Reflector)
+	if !ok {
+		return fmt.Errorf("webhooks require OpenAPI 3.1 reflector, %T received", c.refl())
+	}
+
+	oc, err := r31.NewOperationContext(w.HTTPMethod(), w.Name)
+	if err != nil {
+		return err
+	}
+
+	setupWebhookOC(oc, w)
+
+	return r31.AddWebhook(oc)
+}
+
+// AddCallback adds webhook as a callback of operation, callback is applied when operation is collected.
+//
+// Expression is a runtime expression of callback URL, for example "{$request.body#/callbackUrl}".
+func (c *Collector) AddCallback(method, pattern, expression string, w webhook.Webhook) {
+	c.AnnotateOperation(method, pattern, func(oc openapi.OperationContext) error {
+		if err := c.setupCallback(oc, expression, w); err != nil {
+			return fmt.Errorf("reflect callback %s: %w", w.Name, err)
+		}
+
+		return nil
+	})
+}
+
+func (c *Collector) setupCallback(oc openapi.OperationContext, expression string, w webhook.Webhook) error {
+	reflector := c.refl()
+
+	coc, err := reflector.NewOperationContext(w.HTTPMethod(), callbackPath)
+	if err != nil {
+		return err
+	}
+
+	setupWebhookOC(coc, w)
+
+	if err := reflector.AddOperation(coc); err != nil {
+		return err
+	}
+
+	switch s := reflector.SpecSchema().(type) {
+	case *openapi3.Spec:
+		pi := s.Paths.MapOfPathItemValues[callbackPath]
+		delete(s.Paths.MapOfPathItemValues, callbackPath)
+
+		o3, ok := oc.(openapi3.OperationExposer)
+		if !ok {
+			return fmt.Errorf("unexpected operation context %T", oc)
+		}
+
+		op := o3.Operation()
+		if op.Callbacks == nil {
+			op.Callbacks = make(map[string]openapi3.CallbackOrRef)
+		}
+
+		op.Callbacks[w.Name] = openapi3.CallbackOrRef{
+			Callback: &openapi3.Callback{AdditionalProperties: map[string]openapi3.PathItem{expression: pi}},
+		}
+	case *openapi31.Spec:
+		pi := s.Paths.MapOfPathItemValues[callbackPath]
+		delete(s.Paths.MapOfPathItemValues, callbackPath)
+
+		o31, ok := oc.(openapi31.OperationExposer)
+		if !ok {
+			return fmt.Errorf("unexpected operation context %T", oc)
+		}
+
+		op := o31.Operation()
+		if op.Callbacks == nil {
+			op.Callbacks = make(map[string]openapi31.CallbacksOrReference)
+		}
+
+		op.Callbacks[w.Name] = openapi31.CallbacksOrReference{
+			Callbacks: &openapi31.Callbacks{
+				AdditionalProperties: map[string]openapi31.PathItemOrReference{expression: {PathItem: &pi}},
+			},
+		}
+	default:
+		return fmt.Errorf("callbacks are not supported for %T", s)
+	}
+
+	return nil
+}
+
+func setupWebhookOC(oc openapi.OperationContext, w webhook.Webhook) {
+	if w.Summary != "" {
+		oc.SetSummary(w.Summary)
+	}
+
+	if w.Description != "" {
+		oc.SetDescription(w.Description)
+	}
+
+	if len(w.Tags) > 0 {
+		oc.SetTags(w.Tags...)
+	}
+
+	if w.Payload != nil {
+		oc.AddReqStructure(w.Payload)
+	}
+
+	if w.Response != nil {
+		oc.AddRespStructure(w.Response, openapi.WithHTTPStatus(http.StatusOK))
+	}
+}
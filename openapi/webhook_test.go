@@ -0,0 +1,144 @@
+package openapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/rest/webhook"
+	"github.com/swaggest/usecase"
+)
+
+type taskFinished struct {
+	Event  string `header:"X-Event" json:"-" required:"true"`
+	TaskID int    `json:"taskId" required:"true"`
+	Result string `json:"result" enum:"ok,failed"`
+}
+
+func TestCollector_AddWebhook(t *testing.T) {
+	c := openapi.NewCollector(openapi31.NewReflector())
+
+	require.NoError(t, c.AddWebhook(webhook.Webhook{
+		Name:    "taskFinished",
+		Summary: "Task finished",
+		Tags:    []string{"Tasks"},
+		Payload: taskFinished{},
+	}))
+
+	assert.EqualError(t, c.AddWebhook(webhook.Webhook{Name: "taskFinished"}),
+		"reflect webhook taskFinished: duplicate webhook name: taskFinished")
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"","version":""},"paths":{},
+	  "webhooks":{
+		"taskFinished":{
+		  "post":{
+			"tags":["Tasks"],"summary":"Task finished",
+			"parameters":[{"name":"X-Event","in":"header","required":true,"schema":{"type":"string"}}],
+			"requestBody":{
+			  "content":{
+				"application/json":{"schema":{"$ref":"#/components/schemas/OpenapiTestTaskFinished"}}
+			  }
+			},
+			"responses":{"204":{"description":"No Content"}}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "OpenapiTestTaskFinished":{
+			"required":["taskId"],
+			"properties":{"result":{"enum":["ok","failed"],"type":"string"},"taskId":{"type":"integer"}},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, c.SpecSchema())
+
+	c = openapi.NewCollector(openapi3.NewReflector())
+	assert.EqualError(t, c.AddWebhook(webhook.Webhook{Name: "taskFinished"}),
+		"reflect webhook taskFinished: webhooks require OpenAPI 3.1 reflector, *openapi3.Reflector received")
+}
+
+func TestCollector_AddCallback(t *testing.T) {
+	type createTask struct {
+		CallbackURL string `json:"callbackUrl" required:"true"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, _ createTask, _ *struct{}) error {
+		return nil
+	})
+
+	w := webhook.Webhook{
+		Name:    "taskFinished",
+		Payload: taskFinished{},
+		Response: new(struct {
+			OK bool `json:"ok"`
+		}),
+	}
+
+	for _, c := range []*openapi.Collector{
+		openapi.NewCollector(openapi3.NewReflector()),
+		openapi.NewCollector(openapi31.NewReflector()),
+	} {
+		c.AddCallback(http.MethodPost, "/tasks", "{$request.body#/callbackUrl}", w)
+		require.NoError(t, c.CollectUseCase(http.MethodPost, "/tasks", u, rest.HandlerTrait{}))
+
+		assertjson.EqMarshal(t, `{
+		  "post":{
+			"summary":"Test Collector _ Add Callback",
+			"operationId":"rest/openapi_test.TestCollector_AddCallback",
+			"requestBody":{
+			  "content":{
+				"application/json":{"schema":{"$ref":"#/components/schemas/OpenapiTestCreateTask"}}
+			  }
+			},
+			"responses":{"204":{"description":"No Content"}},
+			"callbacks":{
+			  "taskFinished":{
+				"{$request.body#/callbackUrl}":{
+				  "post":{
+					"parameters":[{"name":"X-Event","in":"header","required":true,"schema":{"type":"string"}}],
+					"requestBody":{
+					  "content":{
+						"application/json":{"schema":{"$ref":"#/components/schemas/OpenapiTestTaskFinished"}}
+					  }
+					},
+					"responses":{
+					  "200":{
+						"description":"OK",
+						"content":{"application/json":{"schema":{"properties":{"ok":{"type":"boolean"}},"type":"object"}}}
+					  }
+					}
+				  }
+				}
+			  }
+			}
+		  }
+		}`, pathItem(t, c, "/tasks"))
+	}
+}
+
+func pathItem(t *testing.T, c *openapi.Collector, path string) interface{} {
+	t.Helper()
+
+	switch s := c.SpecSchema().(type) {
+	case *openapi3.Spec:
+		assert.Len(t, s.Paths.MapOfPathItemValues, 1)
+
+		return s.Paths.MapOfPathItemValues[path]
+	case *openapi31.Spec:
+		assert.Len(t, s.Paths.MapOfPathItemValues, 1)
+
+		return s.Paths.MapOfPathItemValues[path]
+	}
+
+	return nil
+}
@@ -0,0 +1,344 @@
+package rest
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// OptionalValue is implemented by pointers to Optional and Nullable.
+//
+// It allows request decoder to tell explicitly provided values from absent ones.
+type OptionalValue interface {
+	// IsSet reports whether value was explicitly provided.
+	IsSet() bool
+
+	// ApplyDefault assigns textual default value without marking value as provided.
+	ApplyDefault(text string) error
+}
+
+// ValueSchemaExposer is implemented by Optional and Nullable to expose sample of underlying value.
+//
+// It allows OpenAPI collector to reflect schema of value with its own JSON Schema reflector.
+type ValueSchemaExposer interface {
+	JSONSchemaValue() (sample interface{}, nullable bool)
+}
+
+var (
+	_ OptionalValue      = &Optional[int]{}
+	_ OptionalValue      = &Nullable[int]{}
+	_ ValueSchemaExposer = Optional[int]{}
+	_ ValueSchemaExposer = Nullable[int]{}
+)
+
+// Optional is a value that tracks its presence in request.
+//
+// It can be used in fields of request structure with `query`, `header`, `cookie`, `formData`
+// or `json` tags. JSON Schema of Optional[T] is the schema of T.
+//
+// If request decoder applies defaults, absent value receives default, but IsSet still reports false.
+type Optional[T any] struct {
+	value T
+	set   bool
+}
+
+// NewOptional creates provided Optional value.
+func NewOptional[T any](v T) Optional[T] {
+	return Optional[T]{value: v, set: true}
+}
+
+// Get returns value and its presence.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set
+}
+
+// Value returns value, it can be zero or default value if value is not set.
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// IsSet reports whether value was explicitly provided.
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// IsZero reports whether value is absent.
+func (o Optional[T]) IsZero() bool {
+	return !o.set
+}
+
+// Set assigns value.
+func (o *Optional[T]) Set(v T) {
+	o.value = v
+	o.set = true
+}
+
+// Unset resets value to absent state.
+func (o *Optional[T]) Unset() {
+	*o = Optional[T]{}
+}
+
+// ApplyDefault implements OptionalValue.
+func (o *Optional[T]) ApplyDefault(text string) error {
+	return unmarshalValueText([]byte(text), &o.value)
+}
+
+// UnmarshalText decodes value of form parameter.
+func (o *Optional[T]) UnmarshalText(text []byte) error {
+	if err := unmarshalValueText(text, &o.value); err != nil {
+		return err
+	}
+
+	o.set = true
+
+	return nil
+}
+
+// UnmarshalJSON decodes JSON value, null is treated as absent value.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		o.set = false
+
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &o.value); err != nil {
+		return err
+	}
+
+	o.set = true
+
+	return nil
+}
+
+// MarshalJSON encodes value.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.value)
+}
+
+// InlineJSONSchema prevents creation of a separate definition for Optional.
+func (o Optional[T]) InlineJSONSchema() {}
+
+// JSONSchemaValue implements ValueSchemaExposer.
+func (o Optional[T]) JSONSchemaValue() (interface{}, bool) {
+	var v T
+
+	return v, false
+}
+
+// PrepareJSONSchema replaces schema with the schema of T reflected with default reflector.
+func (o Optional[T]) PrepareJSONSchema(s *jsonschema.Schema) error {
+	return prepareValueSchema[T](s, false)
+}
+
+// Nullable is a value that tracks its presence and explicit null in request.
+//
+// It can be used in fields of request structure with `query`, `header`, `cookie`, `formData`
+// or `json` tags. JSON Schema of Nullable[T] is the schema of T that also allows null.
+//
+// Empty form parameter value is decoded as null, unless T is a string.
+//
+// If request decoder applies defaults, absent value receives default, but IsSet still reports false.
+type Nullable[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// NewNullable creates provided non-null Nullable value.
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, set: true}
+}
+
+// Null creates provided null Nullable value.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{set: true, null: true}
+}
+
+// Get returns value and whether it is provided and not null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.set && !n.null
+}
+
+// Value returns value, it can be zero or default value if value is not set or is null.
+func (n Nullable[T]) Value() T {
+	return n.value
+}
+
+// IsSet reports whether value was explicitly provided, including null.
+func (n Nullable[T]) IsSet() bool {
+	return n.set
+}
+
+// IsNull reports whether value was explicitly provided as null.
+func (n Nullable[T]) IsNull() bool {
+	return n.set && n.null
+}
+
+// IsZero reports whether value is absent.
+func (n Nullable[T]) IsZero() bool {
+	return !n.set
+}
+
+// Set assigns non-null value.
+func (n *Nullable[T]) Set(v T) {
+	n.value = v
+	n.set = true
+	n.null = false
+}
+
+// SetNull assigns null value.
+func (n *Nullable[T]) SetNull() {
+	var zero T
+
+	n.value = zero
+	n.set = true
+	n.null = true
+}
+
+// Unset resets value to absent state.
+func (n *Nullable[T]) Unset() {
+	*n = Nullable[T]{}
+}
+
+// ApplyDefault implements OptionalValue.
+func (n *Nullable[T]) ApplyDefault(text string) error {
+	return unmarshalValueText([]byte(text), &n.value)
+}
+
+// UnmarshalText decodes value of form parameter.
+func (n *Nullable[T]) UnmarshalText(text []byte) error {
+	if len(text) == 0 && reflect.ValueOf(&n.value).Elem().Kind() != reflect.String {
+		n.SetNull()
+
+		return nil
+	}
+
+	var v T
+
+	if err := unmarshalValueText(text, &v); err != nil {
+		return err
+	}
+
+	n.Set(v)
+
+	return nil
+}
+
+// UnmarshalJSON decodes JSON value.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		n.SetNull()
+
+		return nil
+	}
+
+	var v T
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	n.Set(v)
+
+	return nil
+}
+
+// MarshalJSON encodes value, absent and null values are encoded as null.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.set || n.null {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.value)
+}
+
+// InlineJSONSchema prevents creation of a separate definition for Nullable.
+func (n Nullable[T]) InlineJSONSchema() {}
+
+// JSONSchemaValue implements ValueSchemaExposer.
+func (n Nullable[T]) JSONSchemaValue() (interface{}, bool) {
+	var v T
+
+	return v, true
+}
+
+// PrepareJSONSchema replaces schema with the nullable schema of T reflected with default reflector.
+func (n Nullable[T]) PrepareJSONSchema(s *jsonschema.Schema) error {
+	return prepareValueSchema[T](s, true)
+}
+
+// prepareValueSchema is used with reflectors that do not intercept ValueSchemaExposer,
+// for example jsonschema.Reflector{}, OpenAPI collector reflects value with its own reflector instead.
+func prepareValueSchema[T any](s *jsonschema.Schema, nullable bool) error {
+	var (
+		v T
+		r jsonschema.Reflector
+	)
+
+	vs, err := r.Reflect(v, jsonschema.InlineRefs)
+	if err != nil {
+		return err
+	}
+
+	vs.ReflectType = s.ReflectType
+	vs.Parent = s.Parent
+
+	if nullable && vs.Type != nil {
+		vs.AddType(jsonschema.Null)
+	}
+
+	*s = vs
+
+	return nil
+}
+
+func unmarshalValueText[T any](text []byte, v *T) error {
+	if tu, ok := interface{}(v).(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText(text)
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	s := string(text)
+
+	switch rv.Kind() { //nolint:exhaustive // Other kinds are decoded as JSON.
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		rv.SetFloat(f)
+	default:
+		return json.Unmarshal(text, v)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package rest_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/request"
+)
+
+type optionalInput struct {
+	Limit  rest.Optional[int]      `query:"limit" default:"10" minimum:"1"`
+	Locale rest.Optional[string]   `header:"X-Locale"`
+	Debug  rest.Nullable[bool]     `cookie:"debug"`
+	Name   rest.Optional[string]   `json:"name" default:"anonymous"`
+	Parent rest.Nullable[int]      `json:"parent" default:"1"`
+	Tags   rest.Optional[[]string] `json:"tags"`
+}
+
+func TestOptional_decode(t *testing.T) {
+	df := request.NewDecoderFactory()
+	df.ApplyDefaults = true
+
+	dec := df.MakeDecoder(http.MethodPost, optionalInput{}, nil)
+
+	req, err := http.NewRequest(http.MethodPost, "/?limit=5", bytes.NewBufferString(`{"parent":null}`))
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Locale", "en-US")
+	req.AddCookie(&http.Cookie{Name: "debug", Value: "true"})
+
+	var in optionalInput
+
+	require.NoError(t, dec.Decode(req, &in, nil))
+
+	v, ok := in.Limit.Get()
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+
+	assert.Equal(t, "en-US", in.Locale.Value())
+	assert.True(t, in.Locale.IsSet())
+
+	d, ok := in.Debug.Get()
+	assert.True(t, ok)
+	assert.True(t, d)
+
+	// Absent value receives default, but is not reported as provided.
+	assert.False(t, in.Name.IsSet())
+	assert.Equal(t, "anonymous", in.Name.Value())
+
+	// Explicit null is kept instead of default.
+	assert.True(t, in.Parent.IsSet())
+	assert.True(t, in.Parent.IsNull())
+	assert.Equal(t, 0, in.Parent.Value())
+
+	assert.False(t, in.Tags.IsSet())
+
+	req, err = http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"","tags":["a"]}`))
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "debug", Value: ""})
+
+	in = optionalInput{}
+
+	require.NoError(t, dec.Decode(req, &in, nil))
+
+	assert.False(t, in.Limit.IsSet())
+	assert.Equal(t, 10, in.Limit.Value())
+	assert.False(t, in.Locale.IsSet())
+	assert.True(t, in.Debug.IsNull())
+	assert.True(t, in.Name.IsSet())
+	assert.Equal(t, "", in.Name.Value())
+	assert.False(t, in.Parent.IsSet())
+	assert.Equal(t, 1, in.Parent.Value())
+	assert.Equal(t, []string{"a"}, in.Tags.Value())
+}
+
+func TestOptional_decode_invalid(t *testing.T) {
+	df := request.NewDecoderFactory()
+	dec := df.MakeDecoder(http.MethodGet, optionalInput{}, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/?limit=abc", nil)
+	require.NoError(t, err)
+
+	var in optionalInput
+
+	err = dec.Decode(req, &in, nil)
+	assert.Equal(t, rest.RequestErrors{
+		"query:limit": []string{`#: strconv.ParseInt: parsing "abc": invalid syntax`},
+	}, err)
+}
+
+func TestOptional_JSONSchema(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(optionalInput{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"name":{"default":"anonymous","type":"string"},
+		"parent":{"default":1,"type":["integer","null"]},
+		"tags":{"items":{"type":"string"},"type":["array","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestNullable_MarshalJSON(t *testing.T) {
+	type out struct {
+		A rest.Nullable[int]    `json:"a"`
+		B rest.Nullable[int]    `json:"b"`
+		C rest.Optional[string] `json:"c"`
+	}
+
+	assertjson.EqMarshal(t, `{"a":null,"b":2,"c":"c"}`, out{
+		A: rest.Null[int](),
+		B: rest.NewNullable(2),
+		C: rest.NewOptional("c"),
+	})
+}
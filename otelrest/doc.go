@@ -0,0 +1,4 @@
+// Package otelrest provides OpenTelemetry tracing and metrics for use case handlers.
+//
+// It is a separate module to keep OpenTelemetry dependencies out of github.com/swaggest/rest.
+package otelrest
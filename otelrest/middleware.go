@@ -0,0 +1,242 @@
+package otelrest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/swaggest/rest/otelrest"
+	requestDurationName = "http.server.request.duration"
+	phaseDurationName   = "rest.server.phase.duration"
+)
+
+// Attribute keys.
+const (
+	OperationKey      = attribute.Key("rest.operation")
+	PhaseKey          = attribute.Key("rest.phase")
+	InvalidFieldsKey  = attribute.Key("rest.invalid_fields")
+	RequestMethodKey  = attribute.Key("http.request.method")
+	RouteKey          = attribute.Key("http.route")
+	ResponseStatusKey = attribute.Key("http.response.status_code")
+	ErrorTypeKey      = attribute.Key("error.type")
+)
+
+// Config controls instrumentation.
+type Config struct {
+	// TracerProvider is used to create spans, default otel.GetTracerProvider().
+	TracerProvider trace.TracerProvider
+
+	// MeterProvider is used to create instruments, default otel.GetMeterProvider().
+	MeterProvider metric.MeterProvider
+
+	// Propagator extracts trace context from request headers, default otel.GetTextMapPropagator().
+	Propagator propagation.TextMapPropagator
+}
+
+// NewMiddleware creates handler middleware that records spans and RED metrics for use case handlers.
+//
+// Span and metric names are based on route pattern and use case name, so that their cardinality is bounded.
+// Request processing phases (decoding, validation, interaction and encoding) are recorded as child spans
+// and are measured with rest.server.phase.duration histogram.
+//
+// Middleware is to be added with web.Service.Wrap or chirouter.Wrapper.Wrap.
+func NewMiddleware(cfg Config) (func(http.Handler) http.Handler, error) {
+	if cfg.TracerProvider == nil {
+		cfg.TracerProvider = otel.GetTracerProvider()
+	}
+
+	if cfg.MeterProvider == nil {
+		cfg.MeterProvider = otel.GetMeterProvider()
+	}
+
+	if cfg.Propagator == nil {
+		cfg.Propagator = otel.GetTextMapPropagator()
+	}
+
+	meter := cfg.MeterProvider.Meter(instrumentationName)
+
+	requestDuration, err := meter.Float64Histogram(requestDurationName,
+		metric.WithDescription("Duration of HTTP server requests."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	phaseDuration, err := meter.Float64Histogram(phaseDurationName,
+		metric.WithDescription("Duration of request processing phases."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	i := instrumentation{
+		tracer:          cfg.TracerProvider.Tracer(instrumentationName),
+		propagator:      cfg.Propagator,
+		requestDuration: requestDuration,
+		phaseDuration:   phaseDuration,
+	}
+
+	return i.middleware, nil
+}
+
+type instrumentation struct {
+	tracer          trace.Tracer
+	propagator      propagation.TextMapPropagator
+	requestDuration metric.Float64Histogram
+	phaseDuration   metric.Float64Histogram
+}
+
+func (i instrumentation) middleware(h http.Handler) http.Handler {
+	if nethttp.IsWrapperChecker(h) {
+		return h
+	}
+
+	var (
+		withRoute rest.HandlerWithRoute
+		handler   *nethttp.Handler
+	)
+
+	if !nethttp.HandlerAs(h, &withRoute) || !nethttp.HandlerAs(h, &handler) {
+		return h
+	}
+
+	method := withRoute.RouteMethod()
+	pattern := withRoute.RoutePattern()
+	name := pattern
+
+	if method != "" {
+		name = method + " " + pattern
+	}
+
+	attrs := []attribute.KeyValue{RouteKey.String(pattern)}
+
+	var hasName usecase.HasName
+	if usecase.As(handler.UseCase(), &hasName) && hasName.Name() != "" {
+		attrs = append(attrs, OperationKey.String(hasName.Name()))
+	}
+
+	handler.PhaseHooks = append(handler.PhaseHooks, i.phaseHook(attrs))
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx := i.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := i.tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+			trace.WithAttributes(RequestMethodKey.String(r.Method)),
+		)
+		defer span.End()
+
+		sw := &nethttp.StatusWriter{ResponseWriter: rw}
+
+		h.ServeHTTP(sw, r.WithContext(ctx))
+
+		code := sw.StatusCode()
+
+		reqAttrs := append(attrs[:len(attrs):len(attrs)],
+			RequestMethodKey.String(r.Method),
+			ResponseStatusKey.Int(code),
+		)
+
+		if code >= http.StatusInternalServerError {
+			reqAttrs = append(reqAttrs, ErrorTypeKey.String(strconv.Itoa(code)))
+
+			span.SetStatus(codes.Error, http.StatusText(code))
+		}
+
+		span.SetAttributes(ResponseStatusKey.Int(code))
+
+		i.requestDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(reqAttrs...))
+	})
+}
+
+func (i instrumentation) phaseHook(attrs []attribute.KeyValue) nethttp.PhaseHook {
+	phaseAttrs := make(map[nethttp.Phase]metric.MeasurementOption)
+
+	for _, p := range []nethttp.Phase{
+		nethttp.PhaseDecode, nethttp.PhaseValidate, nethttp.PhaseInteract, nethttp.PhaseEncode,
+	} {
+		phaseAttrs[p] = metric.WithAttributes(append(attrs[:len(attrs):len(attrs)], PhaseKey.String(string(p)))...)
+	}
+
+	return func(ctx context.Context, phase nethttp.Phase) (context.Context, func(err error)) {
+		start := time.Now()
+		ctx, span := i.tracer.Start(ctx, string(phase), trace.WithAttributes(PhaseKey.String(string(phase))))
+
+		return ctx, func(err error) {
+			if err != nil {
+				if phase == nethttp.PhaseDecode || phase == nethttp.PhaseValidate {
+					// Handler responds to decoding errors with 400 Bad Request.
+					err = status.Wrap(err, status.InvalidArgument)
+				}
+
+				recordError(span, err)
+			}
+
+			span.End()
+
+			i.phaseDuration.Record(ctx, time.Since(start).Seconds(), phaseAttrs[phase])
+		}
+	}
+}
+
+// recordError adds error details to span.
+func recordError(span trace.Span, err error) {
+	code, _ := rest.Err(err)
+
+	attrs := []attribute.KeyValue{ResponseStatusKey.Int(code)}
+
+	if fields := invalidFields(err); len(fields) > 0 {
+		attrs = append(attrs, InvalidFieldsKey.StringSlice(fields))
+	}
+
+	span.RecordError(err)
+	span.SetAttributes(attrs...)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// invalidFields returns sorted names of invalid fields of validation or decoding error.
+func invalidFields(err error) []string {
+	var (
+		validationErrors rest.ValidationErrors
+		requestErrors    rest.RequestErrors
+		errs             map[string][]string
+	)
+
+	switch {
+	case errors.As(err, &validationErrors):
+		errs = validationErrors
+	case errors.As(err, &requestErrors):
+		errs = requestErrors
+	default:
+		return nil
+	}
+
+	fields := make([]string, 0, len(errs))
+	for f := range errs {
+		fields = append(fields, f)
+	}
+
+	sort.Strings(fields)
+
+	return fields
+}
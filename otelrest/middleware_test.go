@@ -0,0 +1,130 @@
+package otelrest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/otelrest"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewMiddleware(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	mw, err := otelrest.NewMiddleware(otelrest.Config{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+	require.NoError(t, err)
+
+	s := web.NewService(openapi3.NewReflector())
+	s.Wrap(mw)
+
+	type getInput struct {
+		ID int `path:"id" minimum:"1"`
+	}
+
+	type getOutput struct {
+		ID int `json:"id"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, in getInput, out *getOutput) error {
+		if in.ID == 13 {
+			return errors.New("unlucky")
+		}
+
+		out.ID = in.ID
+
+		return nil
+	})
+	u.SetName("getItem")
+
+	s.Get("/items/{id}", u)
+
+	for _, path := range []string{"/items/1", "/items/0", "/items/13"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rw := httptest.NewRecorder()
+		s.ServeHTTP(rw, req)
+	}
+
+	ended := spans.Ended()
+	require.Len(t, ended, 14)
+
+	names := make([]string, 0, len(ended))
+	for _, s := range ended {
+		names = append(names, s.Name())
+	}
+
+	assert.Equal(t, []string{
+		"validate", "decode", "interact", "encode", "GET /items/{id}",
+		"validate", "decode", "encode", "GET /items/{id}",
+		"validate", "decode", "interact", "encode", "GET /items/{id}",
+	}, names)
+
+	root := ended[4]
+	assert.Equal(t, trace.SpanKindServer, root.SpanKind())
+	assert.Contains(t, root.Attributes(), attribute.String("rest.operation", "getItem"))
+	assert.Contains(t, root.Attributes(), attribute.String("http.route", "/items/{id}"))
+	assert.Contains(t, root.Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+	assert.Equal(t, root.SpanContext().SpanID(), ended[2].Parent().SpanID())
+
+	invalid := ended[5]
+	assert.Equal(t, codes.Error, invalid.Status().Code)
+	assert.Contains(t, invalid.Attributes(), attribute.StringSlice("rest.invalid_fields", []string{"path:id"}))
+	assert.Contains(t, invalid.Attributes(), attribute.Int("http.response.status_code", http.StatusBadRequest))
+
+	failed := ended[11]
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "unlucky", failed.Status().Description)
+	assert.Contains(t, failed.Attributes(), attribute.Int("http.response.status_code", http.StatusInternalServerError))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	counts := map[string]uint64{}
+
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		h, ok := m.Data.(metricdata.Histogram[float64])
+		require.True(t, ok)
+
+		for _, dp := range h.DataPoints {
+			key := m.Name
+
+			if v, ok := dp.Attributes.Value("rest.phase"); ok {
+				key += " " + v.AsString()
+			}
+
+			if v, ok := dp.Attributes.Value("http.response.status_code"); ok {
+				key += " " + v.Emit()
+			}
+
+			counts[key] += dp.Count
+		}
+	}
+
+	assert.Equal(t, map[string]uint64{
+		"http.server.request.duration 200":    1,
+		"http.server.request.duration 400":    1,
+		"http.server.request.duration 500":    1,
+		"rest.server.phase.duration decode":   3,
+		"rest.server.phase.duration validate": 3,
+		"rest.server.phase.duration interact": 2,
+		"rest.server.phase.duration encode":   3,
+	}, counts)
+}
@@ -0,0 +1,2 @@
+// Package pagination provides reusable paging inputs and paged outputs for list endpoints.
+package pagination
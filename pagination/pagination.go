@@ -0,0 +1,153 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/swaggest/rest"
+)
+
+// Query parameter names used by paging inputs.
+const (
+	PageParam   = "page"
+	LimitParam  = "limit"
+	CursorParam = "cursor"
+)
+
+// PageInput is an embeddable use case input for offset paging with page number and page size.
+//
+// Defaults and maximums are declared with field tags and enforced by request validation.
+type PageInput struct {
+	Page  int `query:"page" default:"1" minimum:"1" description:"Page number, starting from 1."`
+	Limit int `query:"limit" default:"20" minimum:"1" maximum:"100" description:"Maximum number of items per page."`
+}
+
+// Offset returns number of items to skip before current page.
+func (p PageInput) Offset() int {
+	if p.Page < 1 {
+		return 0
+	}
+
+	return (p.Page - 1) * p.Limit
+}
+
+// CursorInput is an embeddable use case input for cursor paging.
+type CursorInput struct {
+	Cursor string `query:"cursor" description:"Opaque cursor from a previous page, empty for the first page."`
+	Limit  int    `query:"limit" default:"20" minimum:"1" maximum:"100" description:"Maximum number of items per page."`
+}
+
+// Page is a generic paged use case output.
+//
+// Use SetPageInfo or SetCursorInfo to enable RFC 8288 Link response header with navigation links.
+// Nil Items are encoded as an empty list.
+type Page[T any] struct {
+	Items      []T    `json:"items" nullable:"false" description:"Items of current page."`
+	Total      *int   `json:"total,omitempty" description:"Total number of items."`
+	NextCursor string `json:"nextCursor,omitempty" description:"Cursor to retrieve next page, empty for the last page."`
+
+	page   int
+	limit  int
+	cursor bool
+}
+
+// MarshalJSON encodes page with empty list of items instead of null.
+func (p Page[T]) MarshalJSON() ([]byte, error) {
+	if p.Items == nil {
+		p.Items = []T{}
+	}
+
+	return json.Marshal(pageJSON[T]{Items: p.Items, Total: p.Total, NextCursor: p.NextCursor})
+}
+
+// pageJSON is a JSON representation of Page.
+type pageJSON[T any] struct {
+	Items      []T    `json:"items"`
+	Total      *int   `json:"total,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// ItemsField implements rest.ItemsEnvelope.
+func (Page[T]) ItemsField() string {
+	return "items"
+}
+
+// SetPageInfo sets total number of items and paging state of offset paging.
+func (p *Page[T]) SetPageInfo(in PageInput, total int) {
+	p.Total = &total
+	p.page = in.Page
+	p.limit = in.Limit
+	p.cursor = false
+}
+
+// SetCursorInfo sets cursor of next page, empty value indicates last page.
+func (p *Page[T]) SetCursorInfo(in CursorInput, nextCursor string) {
+	p.NextCursor = nextCursor
+	p.limit = in.Limit
+	p.cursor = true
+}
+
+// WebLinks implements rest.WithWebLinks.
+func (p *Page[T]) WebLinks(r *http.Request) []rest.WebLink {
+	if p.limit <= 0 {
+		return nil
+	}
+
+	if p.cursor {
+		if p.NextCursor == "" {
+			return nil
+		}
+
+		return []rest.WebLink{
+			{URL: linkURL(r, map[string]string{CursorParam: p.NextCursor, LimitParam: strconv.Itoa(p.limit)}), Rel: "next"},
+		}
+	}
+
+	if p.Total == nil {
+		return nil
+	}
+
+	last := (*p.Total + p.limit - 1) / p.limit
+	if last < 1 {
+		last = 1
+	}
+
+	pageLink := func(page int, rel string) rest.WebLink {
+		return rest.WebLink{
+			URL: linkURL(r, map[string]string{PageParam: strconv.Itoa(page), LimitParam: strconv.Itoa(p.limit)}),
+			Rel: rel,
+		}
+	}
+
+	links := []rest.WebLink{pageLink(1, "first")}
+
+	if p.page > 1 {
+		prev := p.page - 1
+		if prev > last {
+			prev = last
+		}
+
+		links = append(links, pageLink(prev, "prev"))
+	}
+
+	if p.page < last {
+		links = append(links, pageLink(p.page+1, "next"))
+	}
+
+	return append(links, pageLink(last, "last"))
+}
+
+// linkURL makes a URL reference to current request path with replaced query parameters.
+func linkURL(r *http.Request, params map[string]string) string {
+	q := r.URL.Query()
+
+	for k, v := range params {
+		q.Set(k, v)
+	}
+
+	u := url.URL{Path: r.URL.Path, RawPath: r.URL.RawPath, RawQuery: q.Encode()}
+
+	return u.String()
+}
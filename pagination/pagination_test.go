@@ -0,0 +1,180 @@
+package pagination_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/pagination"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+)
+
+type item struct {
+	ID int `json:"id"`
+}
+
+func TestPage_WebLinks(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+
+	type listInput struct {
+		pagination.PageInput
+		Status string `query:"status"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, in listInput, out *pagination.Page[item]) error {
+		for i := in.Offset(); i < in.Offset()+in.Limit && i < 45; i++ {
+			out.Items = append(out.Items, item{ID: i})
+		}
+
+		out.SetPageInfo(in.PageInput, 45)
+
+		return nil
+	})
+	u.SetName("listItems")
+
+	s.Get("/items", u)
+
+	req := httptest.NewRequest(http.MethodGet, "/items?status=done&page=2", nil)
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, `</items?limit=20&page=1&status=done>; rel="first", `+
+		`</items?limit=20&page=1&status=done>; rel="prev", `+
+		`</items?limit=20&page=3&status=done>; rel="next", `+
+		`</items?limit=20&page=3&status=done>; rel="last"`, rw.Header().Get("Link"))
+	assertjson.Equal(t, []byte(`{"items":"<ignore-diff>","total":45}`), rw.Body.Bytes())
+
+	req = httptest.NewRequest(http.MethodGet, "/items?limit=1000", nil)
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assertjson.Equal(t, []byte(`{
+	  "status":"INVALID_ARGUMENT","error":"invalid argument: validation failed",
+	  "context":{"query:limit":["#: must be <= 100/1 but found 1000"]}
+	}`), rw.Body.Bytes(), rw.Body.String())
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+		"/items":{
+		  "get":{
+			"summary":"Test Page _ Web Links","operationId":"listItems",
+			"parameters":[
+			  {
+				"name":"page","in":"query","description":"Page number, starting from 1.",
+				"schema":{
+				  "minimum":1,"type":"integer","description":"Page number, starting from 1.","default":1
+				}
+			  },
+			  {
+				"name":"limit","in":"query","description":"Maximum number of items per page.",
+				"schema":{
+				  "maximum":100,"minimum":1,"type":"integer",
+				  "description":"Maximum number of items per page.","default":20
+				}
+			  },
+			  {"name":"status","in":"query","schema":{"type":"string"}}
+			],
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"headers":{
+				  "Link":{
+					"description":"Links to related resources (RFC 8288).",
+					"style":"simple","schema":{"type":"string"}
+				  }
+				},
+				"content":{
+				  "application/json":{
+					"schema":{"$ref":"#/components/schemas/PaginationPageGithubComSwaggestRestPaginationTestItem"}
+				  }
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "PaginationPageGithubComSwaggestRestPaginationTestItem":{
+			"type":"object",
+			"properties":{
+			  "items":{
+				"type":"array","items":{"$ref":"#/components/schemas/PaginationTestItem"},
+				"description":"Items of current page."
+			  },
+			  "nextCursor":{
+				"type":"string","description":"Cursor to retrieve next page, empty for the last page."
+			  },
+			  "total":{"type":"integer","description":"Total number of items.","nullable":true}
+			}
+		  },
+		  "PaginationTestItem":{"type":"object","properties":{"id":{"type":"integer"}}}
+		}
+	  }
+	}`, s.OpenAPISchema())
+}
+
+func TestPage_WebLinks_cursor(t *testing.T) {
+	p := pagination.Page[item]{}
+	req := httptest.NewRequest(http.MethodGet, "/items?cursor=abc", nil)
+
+	p.SetCursorInfo(pagination.CursorInput{Cursor: "abc", Limit: 10}, "def")
+	assert.Equal(t, `</items?cursor=def&limit=10>; rel="next"`, p.WebLinks(req)[0].String())
+
+	p.SetCursorInfo(pagination.CursorInput{Cursor: "def", Limit: 10}, "")
+	assert.Empty(t, p.WebLinks(req))
+}
+
+func TestPageInput_Offset(t *testing.T) {
+	require.Equal(t, 0, pagination.PageInput{}.Offset())
+	require.Equal(t, 40, pagination.PageInput{Page: 3, Limit: 20}.Offset())
+}
+
+func TestPage_WebLinks_deprecated(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+
+	u := usecase.NewInteractor(func(_ context.Context, in pagination.PageInput, out *pagination.Page[item]) error {
+		out.Items = []item{{ID: 1}}
+		out.SetPageInfo(in, 45)
+
+		return nil
+	})
+	u.SetName("listItems")
+
+	s.Get("/items", usecase.Wrap(u, rest.Deprecation{Link: "/v2/items"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/items?page=2", nil)
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, []string{
+		`</v2/items>; rel="successor-version"`,
+		`</items?limit=20&page=1>; rel="first", </items?limit=20&page=1>; rel="prev", ` +
+			`</items?limit=20&page=3>; rel="next", </items?limit=20&page=3>; rel="last"`,
+	}, rw.Header().Values("Link"))
+}
+
+func TestPage_MarshalJSON(t *testing.T) {
+	j, err := json.Marshal(pagination.Page[item]{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"items":[]}`, string(j))
+
+	total := 1
+	p := &pagination.Page[item]{Items: []item{{ID: 1}}, Total: &total, NextCursor: "abc"}
+
+	j, err = json.Marshal(p)
+	require.NoError(t, err)
+	assert.Equal(t, `{"items":[{"id":1}],"total":1,"nextCursor":"abc"}`, string(j))
+}
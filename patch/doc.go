@@ -0,0 +1,2 @@
+// Package patch provides JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902) use case inputs.
+package patch
@@ -0,0 +1,471 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	validator "github.com/santhosh-tekuri/jsonschema/v3"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/usecase/status"
+)
+
+var _ rest.InputWithJSONBody = JSON[struct{}]{}
+
+// Operation is a JSON Patch operation.
+type Operation struct {
+	Op    string          `json:"op" enum:"add,remove,replace,move,copy,test"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSON is an embeddable use case input that receives JSON Patch (RFC 6902) for a value of type T.
+//
+// Request body is documented and validated as a list of operations with paths limited to properties of T,
+// patched document is validated against JSON schema of T in Apply.
+type JSON[T any] struct {
+	ops []Operation
+}
+
+// UnmarshalJSON decodes JSON Patch document.
+func (p *JSON[T]) UnmarshalJSON(data []byte) error {
+	var ops []Operation
+
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return err
+	}
+
+	if ops == nil {
+		return errors.New("json patch must be a JSON array")
+	}
+
+	for i, op := range ops {
+		switch op.Op {
+		case "add", "replace", "test":
+			if len(op.Value) == 0 {
+				return fmt.Errorf("operation %d (%s %s): missing value", i, op.Op, op.Path)
+			}
+		}
+	}
+
+	p.ops = ops
+
+	return nil
+}
+
+// MarshalJSON encodes JSON Patch document.
+func (p JSON[T]) MarshalJSON() ([]byte, error) {
+	if p.ops == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal(p.ops)
+}
+
+// Operations returns patch operations.
+func (p JSON[T]) Operations() []Operation {
+	return p.ops
+}
+
+// JSONBodyMediaType implements rest.InputWithJSONBody.
+func (JSON[T]) JSONBodyMediaType() string {
+	return JSONPatchMediaType
+}
+
+// JSONBodySchema implements rest.InputWithJSONBody.
+func (JSON[T]) JSONBodySchema(r *jsonschema.Reflector) (jsonschema.Schema, error) {
+	target, err := r.Reflect(new(T), jsonschema.InlineRefs)
+	if err != nil {
+		return target, err
+	}
+
+	op, err := r.Reflect(Operation{}, jsonschema.InlineRefs)
+	if err != nil {
+		return op, err
+	}
+
+	op.Definitions = nil
+	op.WithRequired("op", "path")
+
+	if len(target.Properties) > 0 {
+		names := make([]string, 0, len(target.Properties))
+
+		for name := range target.Properties {
+			names = append(names, regexp.QuoteMeta(escapePointer(name)))
+		}
+
+		sort.Strings(names)
+
+		pattern := "^/(" + strings.Join(names, "|") + ")(/.*)?$"
+		pathSchema := jsonschema.String.ToSchemaOrBool()
+		pathSchema.TypeObject.WithPattern(pattern)
+
+		op.Properties["path"] = pathSchema
+		op.Properties["from"] = pathSchema
+	}
+
+	s := jsonschema.Schema{}
+	s.AddType(jsonschema.Array)
+	s.ItemsEns().WithSchemaOrBool(op.ToSchemaOrBool())
+
+	return s, nil
+}
+
+// Apply applies patch operations to target.
+//
+// Patched document is validated against JSON schema of T, validation failure is returned as
+// rest.ValidationErrors with status.InvalidArgument.
+func (p JSON[T]) Apply(target *T) error {
+	var doc interface{}
+
+	if err := convert(target, &doc); err != nil {
+		return err
+	}
+
+	for i, op := range p.ops {
+		var err error
+
+		if doc, err = applyOperation(doc, op); err != nil {
+			return fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	if err := validate[T](doc); err != nil {
+		return err
+	}
+
+	var res T
+
+	if err := convert(doc, &res); err != nil {
+		return err
+	}
+
+	*target = res
+
+	return nil
+}
+
+// targetSchemas caches compiled JSON schemas by target type.
+var targetSchemas sync.Map
+
+// validate checks patched document against JSON schema of T.
+func validate[T any](doc interface{}) error {
+	schema, err := targetSchema[T]()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	err = schema.Validate(bytes.NewReader(data))
+	if err == nil {
+		return nil
+	}
+
+	var ve *validator.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+
+	return status.Wrap(rest.ValidationErrors{"body": validationMessages(nil, ve)}, status.InvalidArgument)
+}
+
+func targetSchema[T any]() (*validator.Schema, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	if s, ok := targetSchemas.Load(t); ok {
+		if cs, ok := s.(*validator.Schema); ok {
+			return cs, nil
+		}
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(new(T))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := validator.NewCompiler()
+
+	if err := compiler.AddResource("schema.json", bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	compiled, err := compiler.Compile("schema.json")
+	if err != nil {
+		return nil, err
+	}
+
+	targetSchemas.Store(t, compiled)
+
+	return compiled, nil
+}
+
+func validationMessages(messages []string, err *validator.ValidationError) []string {
+	messages = append(messages, err.InstancePtr+": "+err.Message)
+	for _, c := range err.Causes {
+		messages = validationMessages(messages, c)
+	}
+
+	return messages
+}
+
+func applyOperation(doc interface{}, op Operation) (interface{}, error) {
+	var value interface{}
+
+	if len(op.Value) > 0 {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return add(doc, op.Path, value)
+	case "remove":
+		doc, _, err := remove(doc, op.Path)
+
+		return doc, err
+	case "replace":
+		doc, _, err := remove(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		return add(doc, op.Path, value)
+	case "move":
+		doc, v, err := remove(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		return add(doc, op.Path, v)
+	case "copy":
+		v, err := get(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		var c interface{}
+		if err := convert(v, &c); err != nil {
+			return nil, err
+		}
+
+		return add(doc, op.Path, c)
+	case "test":
+		v, err := get(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(v, value) {
+			return nil, errors.New("test failed")
+		}
+
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("unknown operation: %s", op.Op)
+}
+
+// parsePointer splits JSON Pointer (RFC 6901) into reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid pointer: %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > length || (!allowEnd && i == length) {
+		return 0, fmt.Errorf("invalid array index: %q", token)
+	}
+
+	return i, nil
+}
+
+func get(doc interface{}, pointer string) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tokens {
+		switch d := doc.(type) {
+		case map[string]interface{}:
+			v, ok := d[t]
+			if !ok {
+				return nil, fmt.Errorf("path not found: %s", pointer)
+			}
+
+			doc = v
+		case []interface{}:
+			i, err := arrayIndex(t, len(d), false)
+			if err != nil {
+				return nil, err
+			}
+
+			doc = d[i]
+		default:
+			return nil, fmt.Errorf("path not found: %s", pointer)
+		}
+	}
+
+	return doc, nil
+}
+
+// update replaces the value at pointer with the result of fn applied to parent container and last token.
+func update(
+	doc interface{},
+	tokens []string,
+	fn func(parent interface{}, token string) (interface{}, error),
+) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		child, ok := d[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("path not found: %s", tokens[0])
+		}
+
+		child, err := update(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+
+		d[tokens[0]] = child
+
+		return d, nil
+	case []interface{}:
+		i, err := arrayIndex(tokens[0], len(d), false)
+		if err != nil {
+			return nil, err
+		}
+
+		child, err := update(d[i], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+
+		d[i] = child
+
+		return d, nil
+	}
+
+	return nil, fmt.Errorf("path not found: %s", tokens[0])
+}
+
+func add(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return update(doc, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[token] = value
+
+			return p, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(p), true)
+			if err != nil {
+				return nil, err
+			}
+
+			p = append(p, nil)
+			copy(p[i+1:], p[i:])
+			p[i] = value
+
+			return p, nil
+		}
+
+		return nil, fmt.Errorf("path not found: %s", pointer)
+	})
+}
+
+func remove(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+
+	var removed interface{}
+
+	doc, err = update(doc, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			v, ok := p[token]
+			if !ok {
+				return nil, fmt.Errorf("path not found: %s", pointer)
+			}
+
+			removed = v
+
+			delete(p, token)
+
+			return p, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(p), false)
+			if err != nil {
+				return nil, err
+			}
+
+			removed = p[i]
+
+			return append(p[:i], p[i+1:]...), nil
+		}
+
+		return nil, fmt.Errorf("path not found: %s", pointer)
+	})
+
+	return doc, removed, err
+}
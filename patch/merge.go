@@ -0,0 +1,189 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/rest"
+)
+
+// Media types of patch documents.
+const (
+	MergePatchMediaType = "application/merge-patch+json"
+	JSONPatchMediaType  = "application/json-patch+json"
+)
+
+var _ rest.InputWithJSONBody = Merge[struct{}]{}
+
+// Merge is an embeddable use case input that receives JSON Merge Patch (RFC 7396) for a value of type T.
+//
+// Request body is documented and validated with schema of T where all properties are optional and nullable.
+// Presence of top-level fields is tracked, so that absent, null and zero values can be distinguished.
+type Merge[T any] struct {
+	doc map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes merge patch document.
+func (m *Merge[T]) UnmarshalJSON(data []byte) error {
+	var doc map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if doc == nil {
+		return errors.New("merge patch must be a JSON object")
+	}
+
+	m.doc = doc
+
+	return nil
+}
+
+// MarshalJSON encodes merge patch document.
+func (m Merge[T]) MarshalJSON() ([]byte, error) {
+	if m.doc == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(m.doc)
+}
+
+// JSONBodyMediaType implements rest.InputWithJSONBody.
+func (Merge[T]) JSONBodyMediaType() string {
+	return MergePatchMediaType
+}
+
+// JSONBodySchema implements rest.InputWithJSONBody.
+func (Merge[T]) JSONBodySchema(r *jsonschema.Reflector) (jsonschema.Schema, error) {
+	s, err := r.Reflect(new(T), jsonschema.InlineRefs)
+	if err != nil {
+		return s, err
+	}
+
+	s.Definitions = nil
+	optionalNullable(&s)
+
+	return s, nil
+}
+
+// Has returns true if top-level field is present in patch, including null value.
+func (m Merge[T]) Has(field string) bool {
+	_, ok := m.doc[field]
+
+	return ok
+}
+
+// IsNull returns true if top-level field is present in patch with null value.
+func (m Merge[T]) IsNull(field string) bool {
+	v, ok := m.doc[field]
+
+	return ok && bytes.Equal(bytes.TrimSpace(v), []byte("null"))
+}
+
+// Fields returns sorted names of top-level fields present in patch.
+func (m Merge[T]) Fields() []string {
+	fields := make([]string, 0, len(m.doc))
+
+	for k := range m.doc {
+		fields = append(fields, k)
+	}
+
+	sort.Strings(fields)
+
+	return fields
+}
+
+// Apply merges patch into target.
+func (m Merge[T]) Apply(target *T) error {
+	var doc interface{}
+
+	if err := convert(target, &doc); err != nil {
+		return err
+	}
+
+	var p interface{}
+
+	if err := convert(m, &p); err != nil {
+		return err
+	}
+
+	var res T
+
+	if err := convert(mergePatch(doc, p), &res); err != nil {
+		return err
+	}
+
+	*target = res
+
+	return nil
+}
+
+// mergePatch implements RFC 7396 MergePatch function.
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+
+	return t
+}
+
+// optionalNullable removes required constraints and allows null values recursively.
+func optionalNullable(s *jsonschema.Schema) {
+	s.Required = nil
+
+	for name, prop := range s.Properties {
+		if prop.TypeObject == nil {
+			continue
+		}
+
+		optionalNullable(prop.TypeObject)
+
+		if t := prop.TypeObject.Type; t != nil {
+			if t.SimpleTypes != nil && *t.SimpleTypes != jsonschema.Null {
+				prop.TypeObject.Type = (&jsonschema.Type{}).WithSliceOfSimpleTypeValues(*t.SimpleTypes, jsonschema.Null)
+			} else if len(t.SliceOfSimpleTypeValues) > 0 && !hasNull(t.SliceOfSimpleTypeValues) {
+				t.SliceOfSimpleTypeValues = append(t.SliceOfSimpleTypeValues, jsonschema.Null)
+			}
+		}
+
+		s.Properties[name] = prop
+	}
+}
+
+func hasNull(types []jsonschema.SimpleType) bool {
+	for _, t := range types {
+		if t == jsonschema.Null {
+			return true
+		}
+	}
+
+	return false
+}
+
+// convert passes value through JSON encoding.
+func convert(from, to interface{}) error {
+	b, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, to)
+}
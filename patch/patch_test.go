@@ -0,0 +1,305 @@
+package patch_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/patch"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+)
+
+type task struct {
+	Title    string   `json:"title" minLength:"3" required:"true"`
+	Priority int      `json:"priority"`
+	Tags     []string `json:"tags"`
+}
+
+func TestMerge(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+
+	type patchTask struct {
+		ID int `path:"id"`
+		patch.Merge[task]
+	}
+
+	var (
+		fields   []string
+		isNull   bool
+		received int
+	)
+
+	u := usecase.NewInteractor(func(_ context.Context, in patchTask, out *task) error {
+		*out = task{Title: "Foo", Priority: 3, Tags: []string{"a"}}
+
+		fields = in.Fields()
+		isNull = in.IsNull("tags")
+		received = in.ID
+
+		return in.Apply(out)
+	})
+	u.SetName("patchTask")
+
+	s.Patch("/tasks/{id}", u)
+
+	req := httptest.NewRequest(http.MethodPatch, "/tasks/12", bytes.NewBufferString(`{"title":"Bar","tags":null}`))
+	req.Header.Set("Content-Type", patch.MergePatchMediaType)
+
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code, rw.Body.String())
+	assert.Equal(t, `{"title":"Bar","priority":3,"tags":null}`+"\n", rw.Body.String())
+	assert.Equal(t, []string{"tags", "title"}, fields)
+	assert.True(t, isNull)
+	assert.Equal(t, 12, received)
+
+	req = httptest.NewRequest(http.MethodPatch, "/tasks/12", bytes.NewBufferString(`{"title":"B"}`))
+	req.Header.Set("Content-Type", patch.MergePatchMediaType)
+
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assertjson.Equal(t, []byte(`{
+	  "status":"INVALID_ARGUMENT","error":"invalid argument: validation failed",
+	  "context":{"body":["#/title: length must be >= 3, but got 1"]}
+	}`), rw.Body.Bytes())
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+	    "/tasks/{id}":{
+	      "patch":{
+	        "summary":"Test Merge","operationId":"patchTask",
+	        "parameters":[
+	          {
+	            "name":"id","in":"path","required":true,"schema":{"type":"integer"}
+	          }
+	        ],
+	        "requestBody":{
+	          "content":{
+	            "application/merge-patch+json":{
+	              "schema":{
+	                "type":"object",
+	                "properties":{
+	                  "priority":{"type":"integer","nullable":true},
+	                  "tags":{"type":"array","items":{"type":"string"},"nullable":true},
+	                  "title":{"minLength":3,"type":"string","nullable":true}
+	                }
+	              }
+	            }
+	          },
+	          "required":true
+	        },
+	        "responses":{
+	          "200":{
+	            "description":"OK",
+	            "content":{
+	              "application/json":{"schema":{"$ref":"#/components/schemas/PatchTestTask"}}
+	            }
+	          }
+	        }
+	      }
+	    }
+	  },
+	  "components":{
+	    "schemas":{
+	      "PatchTestTask":{
+	        "required":["title"],"type":"object",
+	        "properties":{
+	          "priority":{"type":"integer"},
+	          "tags":{"type":"array","items":{"type":"string"},"nullable":true},
+	          "title":{"minLength":3,"type":"string"}
+	        }
+	      }
+	    }
+	  }
+	}`, s.OpenAPISchema())
+}
+
+func TestJSON(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+
+	type patchTask struct {
+		ID int `path:"id"`
+		patch.JSON[task]
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, in patchTask, out *task) error {
+		*out = task{Title: "Foo", Priority: 3, Tags: []string{"a"}}
+
+		return in.Apply(out)
+	})
+	u.SetName("jsonPatchTask")
+
+	s.Patch("/tasks/{id}", u)
+
+	req := httptest.NewRequest(http.MethodPatch, "/tasks/12", bytes.NewBufferString(`[
+	  {"op":"test","path":"/title","value":"Foo"},
+	  {"op":"replace","path":"/title","value":"Bar"},
+	  {"op":"add","path":"/tags/-","value":"b"},
+	  {"op":"add","path":"/tags/0","value":"c"},
+	  {"op":"copy","from":"/tags/1","path":"/tags/-"},
+	  {"op":"remove","path":"/priority"}
+	]`))
+	req.Header.Set("Content-Type", patch.JSONPatchMediaType)
+
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code, rw.Body.String())
+	assert.Equal(t, `{"title":"Bar","priority":0,"tags":["c","a","b","a"]}`+"\n", rw.Body.String())
+
+	req = httptest.NewRequest(http.MethodPatch, "/tasks/12", bytes.NewBufferString(`[
+	  {"op":"replace","path":"/unknown","value":"Bar"}
+	]`))
+	req.Header.Set("Content-Type", patch.JSONPatchMediaType)
+
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code, rw.Body.String())
+
+	req = httptest.NewRequest(http.MethodPatch, "/tasks/12", bytes.NewBufferString(`[
+	  {"op":"replace","path":"/title","value":"B"}
+	]`))
+	req.Header.Set("Content-Type", patch.JSONPatchMediaType)
+
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code, rw.Body.String())
+	assertjson.Equal(t, []byte(`{
+	  "status":"INVALID_ARGUMENT","error":"invalid argument: validation failed",
+	  "context":{"body":["#/title: length must be >= 3, but got 1"]}
+	}`), rw.Body.Bytes())
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"","version":""},
+	  "paths":{
+	    "/tasks/{id}":{
+	      "patch":{
+	        "summary":"Test JSON","operationId":"jsonPatchTask",
+	        "parameters":[
+	          {
+	            "name":"id","in":"path","required":true,"schema":{"type":"integer"}
+	          }
+	        ],
+	        "requestBody":{
+	          "content":{
+	            "application/json-patch+json":{
+	              "schema":{
+	                "type":"array",
+	                "items":{
+	                  "required":["op","path"],"type":"object",
+	                  "properties":{
+	                    "from":{
+	                      "pattern":"^/(priority|tags|title)(/.*)?$",
+	                      "type":"string"
+	                    },
+	                    "op":{
+	                      "enum":["add","remove","replace","move","copy","test"],
+	                      "type":"string"
+	                    },
+	                    "path":{
+	                      "pattern":"^/(priority|tags|title)(/.*)?$",
+	                      "type":"string"
+	                    },
+	                    "value":{}
+	                  }
+	                }
+	              }
+	            }
+	          },
+	          "required":true
+	        },
+	        "responses":{
+	          "200":{
+	            "description":"OK",
+	            "content":{
+	              "application/json":{"schema":{"$ref":"#/components/schemas/PatchTestTask"}}
+	            }
+	          }
+	        }
+	      }
+	    }
+	  },
+	  "components":{
+	    "schemas":{
+	      "PatchTestTask":{
+	        "required":["title"],"type":"object",
+	        "properties":{
+	          "priority":{"type":"integer"},
+	          "tags":{"type":"array","items":{"type":"string"},"nullable":true},
+	          "title":{"minLength":3,"type":"string"}
+	        }
+	      }
+	    }
+	  }
+	}`, s.OpenAPISchema())
+}
+
+func TestJSON_Apply_testFailed(t *testing.T) {
+	var p struct {
+		patch.JSON[task]
+	}
+
+	require.NoError(t, p.UnmarshalJSON([]byte(`[{"op":"test","path":"/title","value":"Bar"}]`)))
+
+	tt := task{Title: "Foo"}
+	assert.EqualError(t, p.Apply(&tt), "operation 0 (test /title): test failed")
+}
+
+func TestJSON_Apply_validate(t *testing.T) {
+	var p struct {
+		patch.JSON[task]
+	}
+
+	require.NoError(t, p.UnmarshalJSON([]byte(`[{"op":"replace","path":"/priority","value":"high"}]`)))
+
+	tt := task{Title: "Foo", Priority: 1}
+	err := p.Apply(&tt)
+	require.Error(t, err)
+	assert.Equal(t, task{Title: "Foo", Priority: 1}, tt)
+
+	code, er := rest.Err(err)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, map[string]interface{}{"body": []string{
+		"#/priority: expected integer, but got string",
+	}}, er.Context)
+
+	require.NoError(t, p.UnmarshalJSON([]byte(`[{"op":"replace","path":"/title","value":"F"}]`)))
+	err = p.Apply(&tt)
+	assert.Equal(t, rest.ValidationErrors{"body": []string{
+		"#/title: length must be >= 3, but got 1",
+	}}, errors.Unwrap(err))
+	assert.Equal(t, "Foo", tt.Title)
+
+	require.NoError(t, p.UnmarshalJSON([]byte(`[{"op":"remove","path":"/title"}]`)))
+	require.Error(t, p.Apply(&tt))
+
+	require.NoError(t, p.UnmarshalJSON([]byte(`[{"op":"replace","path":"/title","value":"Bar"}]`)))
+	require.NoError(t, p.Apply(&tt))
+	assert.Equal(t, "Bar", tt.Title)
+}
+
+func TestJSON_UnmarshalJSON_missingValue(t *testing.T) {
+	var p patch.JSON[task]
+
+	for _, op := range []string{"add", "replace", "test"} {
+		assert.EqualError(t, p.UnmarshalJSON([]byte(`[{"op":"`+op+`","path":"/title"}]`)),
+			"operation 0 ("+op+" /title): missing value")
+	}
+
+	require.NoError(t, p.UnmarshalJSON([]byte(`[{"op":"add","path":"/tags","value":null},{"op":"remove","path":"/tags"}]`)))
+}
@@ -1,5 +1,7 @@
 package rest
 
+import "github.com/swaggest/jsonschema-go"
+
 // ParamIn defines parameter location.
 type ParamIn string
 
@@ -54,3 +56,13 @@ func (re RequestErrors) Fields() map[string]interface{} {
 
 	return res
 }
+
+// InputWithJSONBody is implemented by use case input that decodes whole JSON request body on its own
+// (with json.Unmarshaler) and has a dedicated media type, for example JSON Merge Patch.
+type InputWithJSONBody interface {
+	// JSONBodyMediaType returns media type of request body, e.g. "application/merge-patch+json".
+	JSONBodyMediaType() string
+
+	// JSONBodySchema returns JSON Schema of request body.
+	JSONBodySchema(r *jsonschema.Reflector) (jsonschema.Schema, error)
+}
@@ -106,6 +106,11 @@ type decoder struct {
 
 var _ nethttp.RequestDecoder = &decoder{}
 
+// ParamsIn returns a copy of request parts that are decoded, in order of decoding.
+func (d *decoder) ParamsIn() []rest.ParamIn {
+	return append([]rest.ParamIn(nil), d.in...)
+}
+
 // Decode populates and validates input with data from http request.
 func (d *decoder) Decode(r *http.Request, input interface{}, validator rest.Validator) error {
 	if d.isReqSetter {
@@ -139,13 +139,20 @@ func (df *DecoderFactory) MakeDecoder(
 
 	hasFormData := refl.HasTaggedFields(input, formDataTag)
 
+	var mediaType string
+
+	withJSONBody, hasJSONBody := input.(rest.InputWithJSONBody)
+	if hasJSONBody {
+		mediaType = withJSONBody.JSONBodyMediaType()
+	}
+
 	// Checking for body tags.
 	if refl.HasTaggedFields(input, jsonTag) || refl.FindEmbeddedSliceOrMap(input) != nil ||
-		refl.IsSliceOrMap(input) || refl.IsScalar(input) {
+		refl.IsSliceOrMap(input) || refl.IsScalar(input) || hasJSONBody {
 		if df.JSONReader != nil {
-			d.decoders = append(d.decoders, decodeJSONBody(df.JSONReader, hasFormData))
+			d.decoders = append(d.decoders, decodeJSONBody(df.JSONReader, hasFormData, mediaType))
 		} else {
-			d.decoders = append(d.decoders, decodeJSONBody(readJSON, hasFormData))
+			d.decoders = append(d.decoders, decodeJSONBody(readJSON, hasFormData, mediaType))
 		}
 
 		d.in = append(d.in, rest.ParamInBody)
@@ -259,13 +266,28 @@ func (df *DecoderFactory) jsonParams(formDecoder *form.Decoder, in rest.ParamIn,
 	}, string(in))
 }
 
+// optionalDefault is a default value of rest.OptionalValue field.
+type optionalDefault struct {
+	index []int
+	value string
+}
+
+var typeOfOptionalValue = reflect.TypeOf((*rest.OptionalValue)(nil)).Elem()
+
 func (df *DecoderFactory) makeDefaultDecoder(input interface{}, m *decoder) {
+	var optionalDefaults []optionalDefault
+
 	defaults := url.Values{}
 
 	refl.WalkFieldsRecursively(reflect.ValueOf(input), func(v reflect.Value, sf reflect.StructField, path []reflect.StructField) {
-		var key string
+		var (
+			key   string
+			index []int
+		)
 
 		for _, p := range path {
+			index = append(index, p.Index...)
+
 			if p.Anonymous {
 				continue
 			}
@@ -283,9 +305,9 @@ func (df *DecoderFactory) makeDefaultDecoder(input interface{}, m *decoder) {
 			key += "[" + sf.Name + "]"
 		}
 
-		if d, ok := sf.Tag.Lookup(defaultTag); ok { //nolint:nestif
-			defaults[key] = []string{d}
-		} else if df.JSONSchemaReflector != nil && v.CanInterface() {
+		d, found := sf.Tag.Lookup(defaultTag)
+
+		if !found && df.JSONSchemaReflector != nil && v.CanInterface() {
 			vi := v.Interface()
 
 			s, err := df.JSONSchemaReflector.Reflect(vi)
@@ -294,28 +316,81 @@ func (df *DecoderFactory) makeDefaultDecoder(input interface{}, m *decoder) {
 			}
 
 			if s.Default != nil {
-				d, err := json.Marshal(s.Default)
+				j, err := json.Marshal(s.Default)
 				if err != nil {
 					panic(err.Error())
 				}
 
-				defaults[key] = []string{strings.Trim(string(d), `"`)}
+				d, found = strings.Trim(string(j), `"`), true
 			}
 		}
+
+		if !found {
+			return
+		}
+
+		// Optional values receive defaults without being marked as provided.
+		if reflect.PtrTo(sf.Type).Implements(typeOfOptionalValue) {
+			optionalDefaults = append(optionalDefaults, optionalDefault{
+				index: append(index, sf.Index...),
+				value: d,
+			})
+
+			return
+		}
+
+		defaults[key] = []string{d}
 	})
 
-	if len(defaults) == 0 {
+	if len(defaults) == 0 && len(optionalDefaults) == 0 {
 		return
 	}
 
 	dec := df.defaultValDecoder
 
 	m.decoders = append(m.decoders, func(_ *http.Request, v interface{}, _ rest.Validator) error {
-		return dec.Decode(v, defaults)
+		if len(defaults) > 0 {
+			if err := dec.Decode(v, defaults); err != nil {
+				return err
+			}
+		}
+
+		return applyOptionalDefaults(v, optionalDefaults)
 	})
 	m.in = append(m.in, defaultTag)
 }
 
+func applyOptionalDefaults(v interface{}, optionalDefaults []optionalDefault) error {
+	rv := reflect.ValueOf(v)
+
+	for _, od := range optionalDefaults {
+		fv := rv
+
+		for _, i := range od.index {
+			for fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+
+				fv = fv.Elem()
+			}
+
+			fv = fv.Field(i)
+		}
+
+		ov, ok := fv.Addr().Interface().(rest.OptionalValue)
+		if !ok || ov.IsSet() {
+			continue
+		}
+
+		if err := ov.ApplyDefault(od.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (df *DecoderFactory) makeCustomMappingDecoder(customMapping rest.RequestMapping, m *decoder) {
 	for in, mapping := range customMapping {
 		dec := form.NewDecoder()
@@ -24,13 +24,17 @@ func readJSON(rd io.Reader, v interface{}) error {
 	return d.Decode(v)
 }
 
-func decodeJSONBody(readJSON func(rd io.Reader, v interface{}) error, tolerateFormData bool) valueDecoderFunc {
+func decodeJSONBody(
+	readJSON func(rd io.Reader, v interface{}) error,
+	tolerateFormData bool,
+	mediaType string,
+) valueDecoderFunc {
 	return func(r *http.Request, input interface{}, validator rest.Validator) error {
 		if r.ContentLength == 0 {
 			return ErrMissingRequestBody
 		}
 
-		if ret, err := checkJSONBodyContentType(r.Header.Get("Content-Type"), tolerateFormData); err != nil {
+		if ret, err := checkJSONBodyContentType(r.Header.Get("Content-Type"), mediaType, tolerateFormData); err != nil {
 			return err
 		} else if ret {
 			return nil
@@ -67,11 +71,17 @@ func decodeJSONBody(readJSON func(rd io.Reader, v interface{}) error, tolerateFo
 	}
 }
 
-func checkJSONBodyContentType(contentType string, tolerateFormData bool) (ret bool, err error) {
+func checkJSONBodyContentType(contentType, mediaType string, tolerateFormData bool) (ret bool, err error) {
 	if contentType == "" {
 		return false, nil
 	}
 
+	// Allow dedicated media type of input, e.g. 'application/merge-patch+json'.
+	if mediaType != "" && len(contentType) >= len(mediaType) &&
+		strings.ToLower(contentType[0:len(mediaType)]) == mediaType {
+		return false, nil
+	}
+
 	if len(contentType) < 16 || strings.ToLower(contentType[0:16]) != "application/json" { // allow 'application/json;charset=UTF-8'
 		if tolerateFormData && (contentType == "application/x-www-form-urlencoded" || contentType == "multipart/form-data") {
 			return true, nil
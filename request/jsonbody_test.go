@@ -25,7 +25,7 @@ func Test_decodeJSONBody(t *testing.T) {
 	}
 
 	i := Input{}
-	assert.NoError(t, decodeJSONBody(readJSON, false)(createReq, &i, nil))
+	assert.NoError(t, decodeJSONBody(readJSON, false, "")(createReq, &i, nil))
 	assert.Equal(t, 123, i.Amount)
 	assert.Equal(t, "248df4b7-aa70-47b8-a036-33ac447e668d", i.CustomerID)
 	assert.Equal(t, "withdraw", i.Type)
@@ -37,7 +37,7 @@ func Test_decodeJSONBody(t *testing.T) {
 	i = Input{}
 	_, err = createBody.Seek(0, io.SeekStart)
 	assert.NoError(t, err)
-	assert.NoError(t, decodeJSONBody(readJSON, false)(createReq, &i, vl))
+	assert.NoError(t, decodeJSONBody(readJSON, false, "")(createReq, &i, vl))
 	assert.Equal(t, 123, i.Amount)
 	assert.Equal(t, "248df4b7-aa70-47b8-a036-33ac447e668d", i.CustomerID)
 	assert.Equal(t, "withdraw", i.Type)
@@ -49,7 +49,7 @@ func Test_decodeJSONBody_emptyBody(t *testing.T) {
 
 	var i []int
 
-	err = decodeJSONBody(readJSON, false)(req, &i, nil)
+	err = decodeJSONBody(readJSON, false, "")(req, &i, nil)
 	assert.EqualError(t, err, "missing request body")
 }
 
@@ -60,7 +60,7 @@ func Test_decodeJSONBody_badContentType(t *testing.T) {
 
 	var i []int
 
-	err = decodeJSONBody(readJSON, false)(req, &i, nil)
+	err = decodeJSONBody(readJSON, false, "")(req, &i, nil)
 	assert.EqualError(t, err, "request with application/json content type expected, received: text/plain")
 }
 
@@ -70,7 +70,7 @@ func Test_decodeJSONBody_decodeFailed(t *testing.T) {
 
 	var i []int
 
-	err = decodeJSONBody(readJSON, false)(req, &i, nil)
+	err = decodeJSONBody(readJSON, false, "")(req, &i, nil)
 	assert.Error(t, err)
 }
 
@@ -80,7 +80,7 @@ func Test_decodeJSONBody_unmarshalFailed(t *testing.T) {
 
 	var i []int
 
-	err = decodeJSONBody(readJSON, false)(req, &i, nil)
+	err = decodeJSONBody(readJSON, false, "")(req, &i, nil)
 	assert.EqualError(t, err, "failed to decode json: json: cannot unmarshal number into Go value of type []int")
 }
 
@@ -94,7 +94,7 @@ func Test_decodeJSONBody_validateFailed(t *testing.T) {
 		return errors.New("failed")
 	})
 
-	err = decodeJSONBody(readJSON, false)(req, &i, vl)
+	err = decodeJSONBody(readJSON, false, "")(req, &i, vl)
 	assert.EqualError(t, err, "failed")
 }
 
@@ -112,7 +112,7 @@ func Test_decodeJSONBody_tolerateFormData(t *testing.T) {
 	}
 
 	i := Input{}
-	assert.NoError(t, decodeJSONBody(readJSON, true)(createReq, &i, nil))
+	assert.NoError(t, decodeJSONBody(readJSON, true, "")(createReq, &i, nil))
 	assert.Empty(t, i.Amount)
 	assert.Empty(t, i.CustomerID)
 	assert.Empty(t, i.Type)
@@ -129,5 +129,27 @@ func Test_decodeJSONBody_charset(t *testing.T) {
 
 	i := Input{}
 
-	assert.NoError(t, decodeJSONBody(readJSON, false)(req, &i, nil))
+	assert.NoError(t, decodeJSONBody(readJSON, false, "")(req, &i, nil))
+}
+
+func Test_decodeJSONBody_mediaType(t *testing.T) {
+	type Input struct {
+		Amount int `json:"amount"`
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, "any", bytes.NewBufferString(`{"amount": 123}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+
+	i := Input{}
+
+	assert.NoError(t, decodeJSONBody(readJSON, false, "application/merge-patch+json")(req, &i, nil))
+	assert.Equal(t, 123, i.Amount)
+
+	req, err = http.NewRequest(http.MethodPatch, "any", bytes.NewBufferString(`{"amount": 123}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+
+	assert.EqualError(t, decodeJSONBody(readJSON, false, "")(req, &i, nil),
+		"request with application/json content type expected, received: application/merge-patch+json")
 }
@@ -1,6 +1,11 @@
 package rest
 
-import "io"
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
 
 // ETagged exposes specific version of resource.
 type ETagged interface {
@@ -17,3 +22,60 @@ type OutputWithHTTPStatus interface {
 	HTTPStatus() int
 	ExpectedHTTPStatuses() []int
 }
+
+// WebLink is a link to a related resource as defined in RFC 8288.
+type WebLink struct {
+	// URL is a target URI reference, it can be relative to the request URL.
+	URL string
+
+	// Rel is a relation type, for example "next" or "prev".
+	Rel string
+}
+
+// String renders link as a Link header value.
+func (l WebLink) String() string {
+	return "<" + l.URL + `>; rel="` + l.Rel + `"`
+}
+
+// WithWebLinks exposes RFC 8288 links to render in Link response header.
+type WithWebLinks interface {
+	WebLinks(r *http.Request) []WebLink
+}
+
+// WebLinksHeader renders links as a Link header value.
+func WebLinksHeader(links []WebLink) string {
+	s := make([]string, 0, len(links))
+
+	for _, l := range links {
+		s = append(s, l.String())
+	}
+
+	return strings.Join(s, ", ")
+}
+
+// OutputFieldsSelector is implemented by use case input to select a subset of output fields (sparse fieldset).
+type OutputFieldsSelector interface {
+	SelectedOutputFields() []string
+}
+
+// ItemsEnvelope is implemented by use case output that wraps a list of items, for example pagination.Page.
+//
+// Sparse fieldset of such output is applied to items instead of output object.
+type ItemsEnvelope interface {
+	// ItemsField returns JSON name of items array.
+	ItemsField() string
+}
+
+type outputFieldsCtxKey struct{}
+
+// WithOutputFields returns context with selected output fields.
+func WithOutputFields(ctx context.Context, fields []string) context.Context {
+	return context.WithValue(ctx, outputFieldsCtxKey{}, fields)
+}
+
+// OutputFields returns selected output fields from context, nil means all fields.
+func OutputFields(ctx context.Context) []string {
+	fields, _ := ctx.Value(outputFieldsCtxKey{}).([]string) //nolint:errcheck // Nil value is a valid result.
+
+	return fields
+}
@@ -42,6 +42,7 @@ type Encoder struct {
 	dynamicWithHeadersSetup bool
 	dynamicSetter           bool
 	dynamicETagged          bool
+	dynamicWebLinks         bool
 	dynamicNoContent        bool
 }
 
@@ -255,6 +256,10 @@ func (h *Encoder) SetupOutput(output interface{}, ht *rest.HandlerTrait) {
 		h.dynamicETagged = true
 	}
 
+	if _, ok := output.(rest.WithWebLinks); ok || h.unwrapInterface {
+		h.dynamicWebLinks = true
+	}
+
 	if _, ok := output.(noContent); ok || h.unwrapInterface {
 		h.dynamicNoContent = true
 	}
@@ -324,14 +329,7 @@ func (h *Encoder) writeJSONResponse(
 	}
 
 	if jw, ok := v.(rest.JSONWriterTo); ok {
-		w.Header().Set("Content-Type", ht.SuccessContentType)
-
-		_, err := jw.JSONWriteTo(w)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-
-			return
-		}
+		h.writeJSONWriterTo(w, r, jw, ht)
 
 		return
 	}
@@ -357,7 +355,18 @@ func (h *Encoder) writeJSONResponse(
 		}
 	}
 
-	w.Header().Set("Content-Length", strconv.Itoa(e.buf.Len()))
+	body := e.buf.Bytes()
+
+	if fields := rest.OutputFields(r.Context()); len(fields) > 0 {
+		body, err = pruneJSON(body, fields, v)
+		if err != nil {
+			h.writeError(status.Wrap(fmt.Errorf("prune response: %w", err), status.Internal), w, r, ht)
+
+			return
+		}
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
 	w.Header().Set("Content-Type", ht.SuccessContentType)
 	w.WriteHeader(ht.SuccessStatus)
 
@@ -365,7 +374,7 @@ func (h *Encoder) writeJSONResponse(
 		return
 	}
 
-	_, err = w.Write(e.buf.Bytes())
+	_, err = w.Write(body)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 
@@ -373,6 +382,39 @@ func (h *Encoder) writeJSONResponse(
 	}
 }
 
+// writeJSONWriterTo writes response of rest.JSONWriterTo, output is buffered to apply sparse fieldset.
+func (h *Encoder) writeJSONWriterTo(w http.ResponseWriter, r *http.Request, jw rest.JSONWriterTo, ht rest.HandlerTrait) {
+	w.Header().Set("Content-Type", ht.SuccessContentType)
+
+	fields := rest.OutputFields(r.Context())
+	if len(fields) == 0 {
+		if _, err := jw.JSONWriteTo(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	if _, err := jw.JSONWriteTo(buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	body, err := pruneJSON(buf.Bytes(), fields, jw)
+	if err != nil {
+		h.writeError(status.Wrap(fmt.Errorf("prune response: %w", err), status.Internal), w, r, ht)
+
+		return
+	}
+
+	if _, err := w.Write(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 // WriteErrResponse encodes and writes error to response.
 func (h *Encoder) WriteErrResponse(w http.ResponseWriter, r *http.Request, statusCode int, response interface{}) {
 	e := jsonEncoderPool.Get().(*jsonEncoder) //nolint:errcheck
@@ -429,6 +471,14 @@ func (h *Encoder) WriteSuccessfulResponse(
 		}
 	}
 
+	if h.dynamicWebLinks {
+		if linked, ok := output.(rest.WithWebLinks); ok {
+			if links := linked.WebLinks(r); len(links) > 0 {
+				w.Header().Add("Link", rest.WebLinksHeader(links))
+			}
+		}
+	}
+
 	if !h.writeHeader(w, r, output, ht) {
 		return
 	}
@@ -1,6 +1,7 @@
 package response_test
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -327,3 +328,91 @@ func TestEncoder_contentTypeRaw(t *testing.T) {
 	assert.Equal(t, "hello,world", w.Body.String())
 	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
 }
+
+type item struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type page struct {
+	Items []item `json:"items"`
+	Total int    `json:"total"`
+}
+
+func (page) ItemsField() string {
+	return "items"
+}
+
+type itemWriter struct {
+	item
+}
+
+func (itemWriter) JSONWriteTo(w io.Writer) (int, error) {
+	return w.Write([]byte(`{"id":1,"title":"foo","body":"bar"}`))
+}
+
+func TestEncoder_WriteSuccessfulResponse_outputFields(t *testing.T) {
+	e := response.Encoder{}
+
+	ht := rest.HandlerTrait{}
+	e.SetupOutput(new(page), &ht)
+
+	r, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	r = r.WithContext(rest.WithOutputFields(r.Context(), []string{"id", "title"}))
+
+	w := httptest.NewRecorder()
+	out := e.MakeOutput(w, ht).(*page) //nolint:errcheck
+	out.Items = []item{{ID: 1, Title: "foo", Body: "bar"}}
+	out.Total = 1
+
+	e.WriteSuccessfulResponse(w, r, out, ht)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"items":[{"id":1,"title":"foo"}],"total":1}`+"\n", w.Body.String())
+	assert.Equal(t, "45", w.Header().Get("Content-Length"))
+
+	for _, items := range [][]item{nil, {}} {
+		w = httptest.NewRecorder()
+		out = e.MakeOutput(w, ht).(*page) //nolint:errcheck
+		out.Items = items
+
+		e.WriteSuccessfulResponse(w, r, out, ht)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		if items == nil {
+			assert.Equal(t, `{"items":null,"total":0}`+"\n", w.Body.String())
+		} else {
+			assert.Equal(t, `{"items":[],"total":0}`+"\n", w.Body.String())
+		}
+	}
+
+	e = response.Encoder{}
+	e.SetupOutput(new(item), &ht)
+
+	w = httptest.NewRecorder()
+	e.WriteSuccessfulResponse(w, r, &item{ID: 1, Title: "foo", Body: "bar"}, ht)
+	assert.Equal(t, `{"id":1,"title":"foo"}`+"\n", w.Body.String())
+
+	// Single resource with array of objects is not an envelope.
+	type order struct {
+		ID    int    `json:"id"`
+		Lines []item `json:"lines"`
+	}
+
+	e = response.Encoder{}
+	e.SetupOutput(new(order), &ht)
+
+	w = httptest.NewRecorder()
+	e.WriteSuccessfulResponse(w, r.WithContext(rest.WithOutputFields(r.Context(), []string{"id"})),
+		&order{ID: 1, Lines: []item{{ID: 2, Title: "foo"}}}, ht)
+	assert.Equal(t, `{"id":1}`+"\n", w.Body.String())
+
+	e = response.Encoder{}
+	e.SetupOutput(new(itemWriter), &ht)
+
+	w = httptest.NewRecorder()
+	e.WriteSuccessfulResponse(w, r, &itemWriter{}, ht)
+	assert.Equal(t, `{"id":1,"title":"foo"}`+"\n", w.Body.String())
+}
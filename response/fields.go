@@ -0,0 +1,67 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/swaggest/rest"
+)
+
+// pruneJSON keeps only selected fields of JSON objects that represent items.
+//
+// Items are elements of a top-level array, or elements of items array of output that implements
+// rest.ItemsEnvelope (for example pagination.Page), otherwise the top-level object itself is pruned.
+func pruneJSON(data []byte, fields []string, output interface{}) ([]byte, error) {
+	var v interface{}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		keep[f] = true
+	}
+
+	switch vv := v.(type) {
+	case []interface{}:
+		pruneItems(vv, keep)
+	case map[string]interface{}:
+		if e, ok := output.(rest.ItemsEnvelope); ok {
+			if items, ok := vv[e.ItemsField()].([]interface{}); ok {
+				pruneItems(items, keep)
+			}
+		} else {
+			pruneObject(vv, keep)
+		}
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(data)))
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func pruneItems(items []interface{}, keep map[string]bool) {
+	for _, item := range items {
+		if obj, ok := item.(map[string]interface{}); ok {
+			pruneObject(obj, keep)
+		}
+	}
+}
+
+func pruneObject(obj map[string]interface{}, keep map[string]bool) {
+	for k := range obj {
+		if !keep[k] {
+			delete(obj, k)
+		}
+	}
+}
@@ -26,20 +26,38 @@ const (
 )
 
 // Middleware enables gzip compression of handler response for requests that accept gzip encoding.
+//
+// It panics on failures to flush or close compressed response, please use MiddlewareWithErrorHandler
+// to handle such failures.
 func Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w = maybeGzipResponseWriter(w, r)
-		if closer, ok := w.(io.Closer); ok {
-			defer func() {
-				err := closer.Close()
-				if err != nil && !errors.Is(err, syscall.EPIPE) {
-					panic(fmt.Sprintf("BUG: cannot close gzip writer: %s", err))
-				}
-			}()
-		}
+	return MiddlewareWithErrorHandler(nil)(next)
+}
 
-		next.ServeHTTP(w, r)
-	})
+// MiddlewareWithErrorHandler enables gzip compression of handler response for requests that accept gzip encoding.
+//
+// Failures to flush or close compressed response are passed to handleErr instead of panic.
+func MiddlewareWithErrorHandler(handleErr func(r *http.Request, err error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w = maybeGzipResponseWriter(w, r, handleErr)
+			if closer, ok := w.(io.Closer); ok {
+				defer func() {
+					err := closer.Close()
+					if err != nil && !errors.Is(err, syscall.EPIPE) {
+						if handleErr != nil {
+							handleErr(r, fmt.Errorf("cannot close gzip writer: %w", err))
+
+							return
+						}
+
+						panic(fmt.Sprintf("BUG: cannot close gzip writer: %s", err))
+					}
+				}()
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
 var (
@@ -80,7 +98,11 @@ func getBufWriter(w io.Writer) *bufio.Writer {
 	return bw
 }
 
-func maybeGzipResponseWriter(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+func maybeGzipResponseWriter(
+	w http.ResponseWriter,
+	r *http.Request,
+	handleErr func(r *http.Request, err error),
+) http.ResponseWriter {
 	ae := r.Header.Get(acceptEncodingHeader)
 	if ae == "" {
 		return w
@@ -96,6 +118,8 @@ func maybeGzipResponseWriter(w http.ResponseWriter, r *http.Request) http.Respon
 		zrw := &gzipResponseWriterHijacker{
 			gzipResponseWriter: gzipResponseWriter{
 				ResponseWriter: w,
+				handleErr:      handleErr,
+				r:              r,
 			},
 			hijacker: hj,
 		}
@@ -105,6 +129,8 @@ func maybeGzipResponseWriter(w http.ResponseWriter, r *http.Request) http.Respon
 
 	zrw := &gzipResponseWriter{
 		ResponseWriter: w,
+		handleErr:      handleErr,
+		r:              r,
 	}
 
 	return zrw
@@ -118,6 +144,9 @@ type gzipResponseWriter struct {
 	expectCompressedBytes bool
 	headersWritten        bool
 	disableCompression    bool
+
+	handleErr func(r *http.Request, err error)
+	r         *http.Request
 }
 
 type gzipResponseWriterHijacker struct {
@@ -280,11 +309,23 @@ func (rw *gzipResponseWriter) Flush() {
 	}
 
 	if err := rw.bufWriter.Flush(); err != nil && !isTrivialNetworkError(err) {
-		panic(fmt.Sprintf("BUG: cannot flush bufio.Writer: %s", err))
+		if rw.handleErr == nil {
+			panic(fmt.Sprintf("BUG: cannot flush bufio.Writer: %s", err))
+		}
+
+		rw.handleErr(rw.r, fmt.Errorf("cannot flush bufio.Writer: %w", err))
+
+		return
 	}
 
 	if err := rw.gzipWriter.Flush(); err != nil && !isTrivialNetworkError(err) {
-		panic(fmt.Sprintf("BUG: cannot flush gzip.Writer: %s", err))
+		if rw.handleErr == nil {
+			panic(fmt.Sprintf("BUG: cannot flush gzip.Writer: %s", err))
+		}
+
+		rw.handleErr(rw.r, fmt.Errorf("cannot flush gzip.Writer: %w", err))
+
+		return
 	}
 
 	if fw, ok := rw.ResponseWriter.(http.Flusher); ok {
@@ -0,0 +1,11 @@
+package rest
+
+import "time"
+
+// WithTimeout is implemented by use case that has to finish interaction within a time limit.
+//
+// Context of interaction is canceled after timeout and response is served with
+// status.DeadlineExceeded error (504 Gateway Timeout).
+type WithTimeout interface {
+	Timeout() time.Duration
+}
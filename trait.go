@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/swaggest/openapi-go"
 	"github.com/swaggest/openapi-go/openapi3"
@@ -39,6 +40,11 @@ type HandlerTrait struct {
 	// RespValidator validates decoded response data.
 	RespValidator Validator
 
+	// Timeout limits duration of use case interaction, it overrides WithTimeout of use case.
+	//
+	// Default is no limit.
+	Timeout time.Duration
+
 	// OperationAnnotations are called after operation setup and before adding operation to documentation.
 	//
 	// Deprecated: use OpenAPIAnnotations.
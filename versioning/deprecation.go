@@ -0,0 +1,6 @@
+package versioning
+
+import "github.com/swaggest/rest"
+
+// Deprecation describes deprecation of a version or an endpoint.
+type Deprecation = rest.Deprecation
@@ -0,0 +1,2 @@
+// Package versioning provides API versions with routes inherited across versions and versioned documentation.
+package versioning
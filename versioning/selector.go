@@ -0,0 +1,68 @@
+package versioning
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Selector defines how request selects API version.
+type Selector struct {
+	pathPrefix     string
+	header         string
+	mediaTypeParam string
+}
+
+// ByPathPrefix selects version by path segment after prefix, e.g. "/api/v2/items" for "/api" prefix.
+//
+// Prefix can be empty to serve versions at "/v1/...", "/v2/..." and so on.
+func ByPathPrefix(prefix string) Selector {
+	return Selector{pathPrefix: strings.TrimRight(prefix, "/")}
+}
+
+// ByHeader selects version by request header value, e.g. "X-API-Version: v2".
+func ByHeader(name string) Selector {
+	return Selector{header: http.CanonicalHeaderKey(name)}
+}
+
+// ByMediaType selects version by parameter of Accept or Content-Type media type,
+// e.g. "Accept: application/json; version=v2" for "version" parameter.
+func ByMediaType(param string) Selector {
+	return Selector{mediaTypeParam: param}
+}
+
+func (s Selector) byPath() bool {
+	return s.header == "" && s.mediaTypeParam == ""
+}
+
+// vary returns request header that affects version selection.
+func (s Selector) vary() string {
+	if s.header != "" {
+		return s.header
+	}
+
+	return "Accept"
+}
+
+// version returns requested version name or empty string.
+func (s Selector) version(r *http.Request) string {
+	if s.header != "" {
+		return strings.TrimSpace(r.Header.Get(s.header))
+	}
+
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mt := range strings.Split(accept, ",") {
+			if _, params, err := mime.ParseMediaType(mt); err == nil && params[s.mediaTypeParam] != "" {
+				return params[s.mediaTypeParam]
+			}
+		}
+	}
+
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		if _, params, err := mime.ParseMediaType(ct); err == nil {
+			return params[s.mediaTypeParam]
+		}
+	}
+
+	return ""
+}
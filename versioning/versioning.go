@@ -0,0 +1,381 @@
+package versioning
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	oapi "github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/rest/response"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// API is a set of API versions.
+//
+// Please use NewAPI to create instance.
+type API struct {
+	// NewService creates web service for a version, default is web.NewService(openapi3.NewReflector()).
+	NewService func(version string) *web.Service
+
+	// DefaultVersion is served when request does not select version with header or media type,
+	// default is the latest added version.
+	DefaultVersion string
+
+	selector Selector
+	versions []*Version
+	byName   map[string]*Version
+
+	mu      sync.Mutex
+	handler http.Handler
+}
+
+// NewAPI creates versioned API.
+func NewAPI(selector Selector, options ...func(a *API)) *API {
+	a := API{
+		selector: selector,
+		byName:   make(map[string]*Version),
+	}
+
+	for _, o := range options {
+		o(&a)
+	}
+
+	return &a
+}
+
+// Version returns API version by name, version is added if it does not exist.
+//
+// Versions are expected to be added from oldest to latest.
+func (a *API) Version(name string) *Version {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if v, ok := a.byName[name]; ok {
+		return v
+	}
+
+	a.mustNotBeBuilt()
+
+	v := &Version{
+		api:         a,
+		name:        name,
+		removed:     make(map[string]bool),
+		deprecation: make(map[string]Deprecation),
+	}
+
+	a.versions = append(a.versions, v)
+	a.byName[name] = v
+
+	return v
+}
+
+// Versions returns names of API versions from oldest to latest.
+func (a *API) Versions() []string {
+	names := make([]string, 0, len(a.versions))
+
+	for _, v := range a.versions {
+		names = append(names, v.name)
+	}
+
+	return names
+}
+
+// ServeHTTP serves request with selected API version.
+//
+// Routes of versions can not be changed after first request.
+func (a *API) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	a.Handler().ServeHTTP(rw, r)
+}
+
+// Handler builds services of API versions and returns a handler that selects version by request.
+func (a *API) Handler() http.Handler {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.handler == nil {
+		a.build()
+	}
+
+	return a.handler
+}
+
+// CombinedCollector returns documentation collector with operations of all versions.
+//
+// With path prefix selector, operations are documented with versioned paths. Otherwise, an operation is
+// documented by the latest version that serves it. Operations are tagged with version name.
+func (a *API) CombinedCollector(refl oapi.Reflector) (*openapi.Collector, error) {
+	c := openapi.NewCollector(refl)
+	c.DefaultSuccessResponseContentType = response.DefaultSuccessResponseContentType
+	c.DefaultErrorResponseContentType = response.DefaultErrorResponseContentType
+
+	seen := make(map[string]bool)
+
+	for i := len(a.versions) - 1; i >= 0; i-- {
+		v := a.versions[i]
+
+		for _, rt := range v.effectiveRoutes() {
+			pattern := rt.pattern
+			if a.selector.byPath() {
+				pattern = a.selector.pathPrefix + "/" + v.name + pattern
+			}
+
+			if seen[rt.method+" "+pattern] {
+				continue
+			}
+
+			seen[rt.method+" "+pattern] = true
+
+			uc := v.routeUseCase(rt)
+			h := nethttp.NewHandler(uc, rt.options...)
+			name := v.name
+
+			err := c.CollectUseCase(rt.method, pattern, uc, h.HandlerTrait, func(oc oapi.OperationContext) error {
+				oc.SetTags(append(oc.Tags(), name)...)
+
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func (a *API) mustNotBeBuilt() {
+	if a.handler != nil {
+		panic("versioned API is already being served")
+	}
+}
+
+func (a *API) build() {
+	if a.NewService == nil {
+		a.NewService = func(_ string) *web.Service {
+			return web.NewService(openapi3.NewReflector())
+		}
+	}
+
+	for _, v := range a.versions {
+		v.service = a.NewService(v.name)
+
+		for _, rt := range v.effectiveRoutes() {
+			v.service.Method(rt.method, rt.pattern, nethttp.NewHandler(v.routeUseCase(rt), rt.options...))
+		}
+	}
+
+	if a.selector.byPath() {
+		r := chi.NewRouter()
+
+		for _, v := range a.versions {
+			r.Mount(a.selector.pathPrefix+"/"+v.name, v.service)
+		}
+
+		a.handler = r
+
+		return
+	}
+
+	defaultVersion := a.DefaultVersion
+	if defaultVersion == "" && len(a.versions) > 0 {
+		defaultVersion = a.versions[len(a.versions)-1].name
+	}
+
+	a.handler = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Add("Vary", a.selector.vary())
+
+		name := a.selector.version(r)
+		if name == "" {
+			name = defaultVersion
+		}
+
+		v, ok := a.byName[name]
+		if !ok {
+			code, er := rest.Err(status.Wrap(errors.New("unknown API version: "+name), status.InvalidArgument))
+
+			var enc response.Encoder
+			enc.WriteErrResponse(rw, r, code, er)
+
+			return
+		}
+
+		if a.selector.header != "" {
+			rw.Header().Set(a.selector.header, v.name)
+		}
+
+		v.service.ServeHTTP(rw, r)
+	})
+}
+
+type route struct {
+	method  string
+	pattern string
+	uc      usecase.Interactor
+	options []func(h *nethttp.Handler)
+
+	// deprecation is set for an explicitly deprecated route and is kept by inheriting versions.
+	deprecation *Deprecation
+}
+
+func (rt route) key() string {
+	return rt.method + " " + rt.pattern
+}
+
+// Version is a revision of API with its own set of routes.
+type Version struct {
+	api    *API
+	name   string
+	parent *Version
+
+	routes      []route
+	removed     map[string]bool
+	deprecation map[string]Deprecation
+	deprecated  *Deprecation
+
+	service *web.Service
+}
+
+// Name returns version name.
+func (v *Version) Name() string {
+	return v.name
+}
+
+// Inherit makes version serve routes of parent version unless they are overridden or removed.
+func (v *Version) Inherit(parent *Version) *Version {
+	v.api.mustNotBeBuilt()
+
+	v.parent = parent
+
+	return v
+}
+
+// Deprecate marks all routes of version as deprecated.
+func (v *Version) Deprecate(d Deprecation) *Version {
+	v.api.mustNotBeBuilt()
+
+	v.deprecated = &d
+
+	return v
+}
+
+// DeprecateRoute marks own or inherited route as deprecated, deprecation is kept by inheriting versions.
+func (v *Version) DeprecateRoute(method, pattern string, d Deprecation) {
+	v.api.mustNotBeBuilt()
+
+	v.deprecation[method+" "+pattern] = d
+}
+
+// Remove excludes inherited route from version.
+func (v *Version) Remove(method, pattern string) {
+	v.api.mustNotBeBuilt()
+
+	v.removed[method+" "+pattern] = true
+}
+
+// Service returns web service of version, it can be used to serve version documentation.
+func (v *Version) Service() *web.Service {
+	v.api.Handler()
+
+	return v.service
+}
+
+// Method adds or overrides the route `pattern` that matches `method` http method to invoke use case interactor.
+func (v *Version) Method(method, pattern string, uc usecase.Interactor, options ...func(h *nethttp.Handler)) {
+	v.api.mustNotBeBuilt()
+
+	rt := route{method: method, pattern: pattern, uc: uc, options: options}
+
+	for i, r := range v.routes {
+		if r.key() == rt.key() {
+			v.routes[i] = rt
+
+			return
+		}
+	}
+
+	v.routes = append(v.routes, rt)
+}
+
+// Delete adds the route `pattern` that matches a DELETE http method to invoke use case interactor.
+func (v *Version) Delete(pattern string, uc usecase.Interactor, options ...func(h *nethttp.Handler)) {
+	v.Method(http.MethodDelete, pattern, uc, options...)
+}
+
+// Get adds the route `pattern` that matches a GET http method to invoke use case interactor.
+func (v *Version) Get(pattern string, uc usecase.Interactor, options ...func(h *nethttp.Handler)) {
+	v.Method(http.MethodGet, pattern, uc, options...)
+}
+
+// Patch adds the route `pattern` that matches a PATCH http method to invoke use case interactor.
+func (v *Version) Patch(pattern string, uc usecase.Interactor, options ...func(h *nethttp.Handler)) {
+	v.Method(http.MethodPatch, pattern, uc, options...)
+}
+
+// Post adds the route `pattern` that matches a POST http method to invoke use case interactor.
+func (v *Version) Post(pattern string, uc usecase.Interactor, options ...func(h *nethttp.Handler)) {
+	v.Method(http.MethodPost, pattern, uc, options...)
+}
+
+// Put adds the route `pattern` that matches a PUT http method to invoke use case interactor.
+func (v *Version) Put(pattern string, uc usecase.Interactor, options ...func(h *nethttp.Handler)) {
+	v.Method(http.MethodPut, pattern, uc, options...)
+}
+
+// effectiveRoutes returns own routes and routes inherited from parent versions.
+func (v *Version) effectiveRoutes() []route {
+	var routes []route
+
+	if v.parent != nil {
+		for _, rt := range v.parent.effectiveRoutes() {
+			if !v.removed[rt.key()] {
+				routes = append(routes, rt)
+			}
+		}
+	}
+
+	for _, rt := range v.routes {
+		overridden := false
+
+		for i, r := range routes {
+			if r.key() == rt.key() {
+				routes[i] = rt
+				overridden = true
+
+				break
+			}
+		}
+
+		if !overridden {
+			routes = append(routes, rt)
+		}
+	}
+
+	for i, rt := range routes {
+		if d, ok := v.deprecation[rt.key()]; ok {
+			d := d
+			routes[i].deprecation = &d
+		}
+	}
+
+	return routes
+}
+
+// routeUseCase returns use case of a route in version, wrapped with effective deprecation.
+func (v *Version) routeUseCase(rt route) usecase.Interactor {
+	if rt.deprecation != nil {
+		return usecase.Wrap(rt.uc, *rt.deprecation)
+	}
+
+	if v.deprecated != nil {
+		return usecase.Wrap(rt.uc, *v.deprecated)
+	}
+
+	return rt.uc
+}
@@ -0,0 +1,145 @@
+package versioning_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/versioning"
+	"github.com/swaggest/usecase"
+)
+
+type itemsOutput struct {
+	Version string `json:"version"`
+}
+
+func items(version string) usecase.Interactor {
+	u := usecase.NewInteractor(func(_ context.Context, _ struct{}, out *itemsOutput) error {
+		out.Version = version
+
+		return nil
+	})
+	u.SetName("items" + version)
+
+	return u
+}
+
+func status() usecase.Interactor {
+	u := usecase.NewInteractor(func(_ context.Context, _ struct{}, out *itemsOutput) error {
+		out.Version = "any"
+
+		return nil
+	})
+	u.SetName("status")
+
+	return u
+}
+
+func newAPI(selector versioning.Selector) *versioning.API {
+	a := versioning.NewAPI(selector)
+
+	v1 := a.Version("v1")
+	v1.Get("/items", items("v1"))
+	v1.Get("/status", status())
+	v1.Get("/legacy", status())
+	v1.DeprecateRoute(http.MethodGet, "/status", versioning.Deprecation{
+		Sunset: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	v2 := a.Version("v2").Inherit(v1)
+	v2.Get("/items", items("v2"))
+	v2.Remove(http.MethodGet, "/legacy")
+
+	return a
+}
+
+func serve(t *testing.T, h http.Handler, path string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	return rw
+}
+
+func TestByPathPrefix(t *testing.T) {
+	a := newAPI(versioning.ByPathPrefix("/api"))
+
+	rw := serve(t, a, "/api/v1/items", nil)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, `{"version":"v1"}`+"\n", rw.Body.String())
+
+	rw = serve(t, a, "/api/v2/items", nil)
+	assert.Equal(t, `{"version":"v2"}`+"\n", rw.Body.String())
+
+	rw = serve(t, a, "/api/v2/status", nil)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "true", rw.Header().Get("Deprecation"))
+	assert.Equal(t, "Tue, 01 Jan 2030 00:00:00 GMT", rw.Header().Get("Sunset"))
+
+	rw = serve(t, a, "/api/v1/legacy", nil)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Empty(t, rw.Header().Get("Deprecation"))
+
+	rw = serve(t, a, "/api/v2/legacy", nil)
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+
+	assert.Equal(t, []string{"v1", "v2"}, a.Versions())
+	assert.Len(t, a.Version("v2").Service().OpenAPICollector.Reflector().Spec.Paths.MapOfPathItemValues, 2)
+
+	c, err := a.CombinedCollector(openapi3.NewReflector())
+	require.NoError(t, err)
+
+	paths := c.Reflector().Spec.Paths.MapOfPathItemValues
+	assert.Len(t, paths, 5)
+	assert.Equal(t, []string{"v2"}, paths["/api/v2/items"].MapOfOperationValues["get"].Tags)
+	assert.True(t, *paths["/api/v1/status"].MapOfOperationValues["get"].Deprecated)
+}
+
+func TestByHeader(t *testing.T) {
+	a := newAPI(versioning.ByHeader("X-API-Version"))
+
+	rw := serve(t, a, "/items", http.Header{"X-Api-Version": []string{"v1"}})
+	assert.Equal(t, `{"version":"v1"}`+"\n", rw.Body.String())
+	assert.Equal(t, "v1", rw.Header().Get("X-API-Version"))
+	assert.Equal(t, "X-Api-Version", rw.Header().Get("Vary"))
+
+	rw = serve(t, a, "/items", nil)
+	assert.Equal(t, `{"version":"v2"}`+"\n", rw.Body.String())
+
+	rw = serve(t, a, "/items", http.Header{"X-Api-Version": []string{"v3"}})
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assertjson.Equal(t, []byte(`{"status":"INVALID_ARGUMENT","error":"invalid argument: unknown API version: v3"}`),
+		rw.Body.Bytes())
+
+	c, err := a.CombinedCollector(openapi3.NewReflector())
+	require.NoError(t, err)
+
+	paths := c.Reflector().Spec.Paths.MapOfPathItemValues
+	assert.Len(t, paths, 3)
+	assert.Equal(t, []string{"v2"}, paths["/items"].MapOfOperationValues["get"].Tags)
+	assert.Equal(t, []string{"v1"}, paths["/legacy"].MapOfOperationValues["get"].Tags)
+}
+
+func TestByMediaType(t *testing.T) {
+	a := newAPI(versioning.ByMediaType("version"))
+
+	rw := serve(t, a, "/items", http.Header{"Accept": []string{"text/html, application/json; version=v1"}})
+	assert.Equal(t, `{"version":"v1"}`+"\n", rw.Body.String())
+	assert.Equal(t, "Accept", rw.Header().Get("Vary"))
+
+	rw = serve(t, a, "/items", http.Header{"Accept": []string{"application/json"}})
+	assert.Equal(t, `{"version":"v2"}`+"\n", rw.Body.String())
+}
@@ -0,0 +1,141 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	oapi "github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/usecase"
+)
+
+const allowHeaderDescription = "Allowed methods."
+
+// enableAutoOptions sets up automatic OPTIONS and 405 responses.
+func (s *Service) enableAutoOptions() {
+	s.OnMethodNotAllowed(usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error {
+		return rest.HTTPCodeAsError(http.StatusMethodNotAllowed)
+	}))
+
+	if s.DocumentAutoOptions {
+		s.autoOptionsPatterns = make(map[string]bool)
+		s.Wrap(s.documentAutoOptions)
+	}
+}
+
+// documentAutoOptions adds 405 response to operations and OPTIONS operations to documented paths.
+func (s *Service) documentAutoOptions(h http.Handler) http.Handler {
+	if nethttp.IsWrapperChecker(h) {
+		return h
+	}
+
+	var (
+		withRoute rest.HandlerWithRoute
+		handler   *nethttp.Handler
+	)
+
+	if !nethttp.HandlerAs(h, &withRoute) || !nethttp.HandlerAs(h, &handler) {
+		return h
+	}
+
+	method := withRoute.RouteMethod()
+	pattern := withRoute.RoutePattern()
+
+	handler.OpenAPIAnnotations = append(handler.OpenAPIAnnotations, func(oc oapi.OperationContext) error {
+		oc.AddRespStructure(rest.ErrResponse{},
+			oapi.WithHTTPStatus(http.StatusMethodNotAllowed),
+			oapi.WithContentType(s.OpenAPICollector.DefaultErrorResponseContentType),
+			openapi.WithResponseHeader("Allow", allowHeaderDescription),
+		)
+
+		return nil
+	})
+
+	if method == http.MethodOptions {
+		// Explicit OPTIONS operation replaces automatic one.
+		if s.autoOptionsPatterns[pattern] {
+			s.removeAutoOptions(pattern)
+		}
+
+		s.autoOptionsPatterns[pattern] = false
+
+		return h
+	}
+
+	if _, seen := s.autoOptionsPatterns[pattern]; seen || method == "" {
+		return h
+	}
+
+	s.autoOptionsPatterns[pattern] = true
+
+	err := s.OpenAPICollector.CollectOperation(http.MethodOptions, pattern, func(oc oapi.OperationContext) error {
+		oc.SetSummary("Allowed methods")
+
+		if params := pathParams(pattern); params != nil {
+			oc.AddReqStructure(params)
+		}
+
+		oc.AddRespStructure(nil,
+			oapi.WithHTTPStatus(http.StatusNoContent),
+			openapi.WithResponseHeader("Allow", allowHeaderDescription),
+		)
+
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return h
+}
+
+var pathParamRegex = regexp.MustCompile(`{([^}:]+)(:[^}]*)?}`)
+
+// pathParams creates request structure with path parameters of a pattern.
+func pathParams(pattern string) interface{} {
+	matches := pathParamRegex.FindAllStringSubmatch(pattern, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	fields := make([]reflect.StructField, 0, len(matches))
+
+	for i, m := range matches {
+		fields = append(fields, reflect.StructField{
+			Name: "P" + strconv.Itoa(i),
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(`path:"` + strings.TrimSpace(m[1]) + `"`),
+		})
+	}
+
+	return reflect.New(reflect.StructOf(fields)).Elem().Interface()
+}
+
+// removeAutoOptions removes automatically documented OPTIONS operation.
+func (s *Service) removeAutoOptions(pattern string) {
+	defer s.OpenAPICollector.InvalidateCache()
+
+	switch spec := s.OpenAPISchema().(type) {
+	case *openapi3.Spec:
+		if pi, ok := spec.Paths.MapOfPathItemValues[pattern]; ok {
+			delete(pi.MapOfOperationValues, "options")
+		}
+	case *openapi31.Spec:
+		if spec.Paths == nil {
+			return
+		}
+
+		if pi, ok := spec.Paths.MapOfPathItemValues[pattern]; ok {
+			pi.Options = nil
+			spec.Paths.MapOfPathItemValues[pattern] = pi
+		}
+	}
+}
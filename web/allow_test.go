@@ -0,0 +1,72 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+)
+
+func TestService_AutoOptions(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector(), func(s *web.Service) {
+		s.AutoOptions = true
+		s.DocumentAutoOptions = true
+	})
+
+	s.Get("/albums/{id}", albumByID())
+	s.Delete("/albums/{id}", albumByID())
+	s.Post("/albums", postAlbums())
+	s.Options("/albums", usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error {
+		return nil
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/albums/1", nil)
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	assert.Equal(t, "GET, DELETE, OPTIONS", rw.Header().Get("Allow"))
+
+	req = httptest.NewRequest(http.MethodPut, "/albums/1", nil)
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	assert.Equal(t, "GET, DELETE, OPTIONS", rw.Header().Get("Allow"))
+	assertjson.Equal(t, []byte(`{"error":"Method Not Allowed"}`), rw.Body.Bytes())
+
+	// Explicit OPTIONS use case is not replaced.
+	req = httptest.NewRequest(http.MethodPut, "/albums", nil)
+	rw = httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	assert.Equal(t, "POST, OPTIONS", rw.Header().Get("Allow"))
+
+	assertjson.EqMarshal(t, `{
+	  "summary":"Allowed methods",
+	  "parameters":[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}],
+	  "responses":{
+		"204":{
+		  "description":"No Content",
+		  "headers":{"Allow":{"style":"simple","description":"Allowed methods.","schema":{"type":"string"}}}
+		}
+	  }
+	}`, s.OpenAPICollector.Reflector().Spec.Paths.MapOfPathItemValues["/albums/{id}"].MapOfOperationValues["options"])
+
+	assertjson.EqMarshal(t, `{
+	  "description":"Method Not Allowed",
+	  "headers":{"Allow":{"style":"simple","description":"Allowed methods.","schema":{"type":"string"}}},
+	  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/RestErrResponse"}}}
+	}`, s.OpenAPICollector.Reflector().Spec.Paths.MapOfPathItemValues["/albums/{id}"].
+		MapOfOperationValues["get"].Responses.MapOfResponseOrRefValues["405"])
+
+	assert.NotEmpty(t, s.OpenAPICollector.Reflector().Spec.Paths.MapOfPathItemValues["/albums"].
+		MapOfOperationValues["options"].ID)
+}
@@ -0,0 +1,260 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/swaggest/rest/request"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// BatchConfig controls batch handler.
+type BatchConfig struct {
+	// MaxItems limits number of sub-requests in a batch, default 20.
+	MaxItems int
+
+	// Concurrency limits number of sub-requests served at the same time, default 4.
+	Concurrency int
+
+	// InheritHeaders are names of headers to copy from batch request to sub-requests,
+	// for example "Authorization". Headers of sub-request take precedence.
+	InheritHeaders []string
+}
+
+// BatchRequest describes a sub-request of batch.
+type BatchRequest struct {
+	Method  string            `json:"method" required:"true" enum:"GET,HEAD,POST,PUT,PATCH,DELETE"`
+	Path    string            `json:"path" required:"true" pattern:"^/" description:"Path with query string."`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty" description:"JSON body, sent as application/json by default."`
+}
+
+// BatchResponse describes a response to sub-request of batch.
+type BatchResponse struct {
+	Status  int                 `json:"status"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    json.RawMessage     `json:"body,omitempty" description:"JSON body or a string with non-JSON body."`
+}
+
+type batchInput struct {
+	request.EmbeddedSetter
+	Requests []BatchRequest `json:"requests" required:"true" minItems:"1"`
+}
+
+type batchOutput struct {
+	Responses []BatchResponse `json:"responses"`
+}
+
+// Batch adds the route `pattern` that matches a POST http method to serve multiple sub-requests at once.
+//
+// Each sub-request is served in-process by service router with all middlewares, responses are returned in order
+// of sub-requests.
+func (s *Service) Batch(pattern string, options ...func(c *BatchConfig)) {
+	c := BatchConfig{
+		MaxItems:    20,
+		Concurrency: 4,
+	}
+
+	for _, o := range options {
+		o(&c)
+	}
+
+	u := usecase.NewInteractor(func(ctx context.Context, in batchInput, out *batchOutput) error {
+		if ctx.Value(batchItemCtxKey{}) != nil {
+			return status.Wrap(errors.New("nested batch is not allowed"), status.InvalidArgument)
+		}
+
+		if len(in.Requests) > c.MaxItems {
+			return status.Wrap(errors.New("too many requests in batch"), status.InvalidArgument)
+		}
+
+		out.Responses = make([]BatchResponse, len(in.Requests))
+
+		var (
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, c.Concurrency)
+		)
+
+		for i, br := range in.Requests {
+			i, br := i, br
+
+			wg.Add(1)
+
+			sem <- struct{}{}
+
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				out.Responses[i] = s.serveBatchItem(ctx, c, in.Request(), br)
+			}()
+		}
+
+		wg.Wait()
+
+		return nil
+	})
+
+	u.SetName("batch")
+	u.SetTitle("Batch")
+	u.SetDescription("Serves multiple API requests in one round trip.")
+	u.SetExpectedErrors(status.InvalidArgument)
+
+	s.Post(pattern, u)
+}
+
+func (s *Service) serveBatchItem(
+	ctx context.Context,
+	c BatchConfig,
+	parent *http.Request,
+	br BatchRequest,
+) BatchResponse {
+	// Sub-requests are marked to reject nested batches regardless of spelling of batch path.
+	ctx = context.WithValue(ctx, batchItemCtxKey{}, true)
+
+	r, err := newSubRequest(ctx, br.Method, br.Path, bytes.NewReader(br.Body))
+	if err != nil {
+		return batchError(http.StatusBadRequest, err.Error())
+	}
+
+	inheritHeaders(parent, r, c.InheritHeaders)
+
+	for k, v := range br.Headers {
+		r.Header.Set(k, v)
+	}
+
+	if len(br.Body) > 0 && r.Header.Get("Content-Type") == "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	rw := s.serveSubRequest(r)
+
+	resp := BatchResponse{Status: rw.status}
+
+	if len(rw.header) > 0 {
+		resp.Headers = rw.header
+	}
+
+	resp.Body = rw.jsonBody()
+
+	return resp
+}
+
+// batchItemCtxKey marks context of batch sub-request.
+type batchItemCtxKey struct{}
+
+// newSubRequest creates a request to be served in-process within another request.
+func newSubRequest(ctx context.Context, method, target string, body io.Reader) (*http.Request, error) {
+	// Route context of parent request is reset to route sub-request from scratch.
+	return http.NewRequestWithContext(context.WithValue(ctx, chi.RouteCtxKey, nil), method, target, body)
+}
+
+// inheritHeaders copies named headers and remote address of parent request to sub-request.
+func inheritHeaders(parent, r *http.Request, names []string) {
+	if parent == nil {
+		return
+	}
+
+	r.RemoteAddr = parent.RemoteAddr
+
+	for _, h := range names {
+		if v, ok := parent.Header[http.CanonicalHeaderKey(h)]; ok {
+			r.Header[http.CanonicalHeaderKey(h)] = v
+		}
+	}
+}
+
+// serveSubRequest serves request in-process with service router and captures response.
+//
+// Panic is recovered with 500 response, because sub-requests are served in separate goroutines.
+func (s *Service) serveSubRequest(r *http.Request) (rw *batchResponseWriter) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			s.handlePanic(r, fmt.Errorf("panic in %s %s: %v\n%s", r.Method, r.URL.Path, rec, debug.Stack()))
+
+			rw = &batchResponseWriter{header: http.Header{"Content-Type": []string{"application/json"}}}
+			rw.status = http.StatusInternalServerError
+			rw.body.WriteString(`{"error":"internal error"}`)
+		}
+	}()
+
+	rw = &batchResponseWriter{header: make(http.Header)}
+	s.ServeHTTP(rw, r)
+
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+
+	return rw
+}
+
+// handlePanic reports recovered panic with HandleInternalError, default is log.Println.
+func (s *Service) handlePanic(r *http.Request, err error) {
+	if s.HandleInternalError != nil {
+		s.HandleInternalError(r, err)
+	} else {
+		log.Println(err)
+	}
+}
+
+func batchError(code int, msg string) BatchResponse {
+	body, _ := json.Marshal(map[string]string{"error": msg}) //nolint:errcheck // Map of strings is always marshaled.
+
+	return BatchResponse{
+		Status:  code,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+		Body:    body,
+	}
+}
+
+// batchResponseWriter captures response of sub-request.
+type batchResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *batchResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *batchResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+}
+
+func (w *batchResponseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.body.Write(data)
+}
+
+// jsonBody returns JSON response body as is or non-JSON body as JSON string.
+func (w *batchResponseWriter) jsonBody() json.RawMessage {
+	if w.body.Len() == 0 {
+		return nil
+	}
+
+	if json.Valid(w.body.Bytes()) {
+		return w.body.Bytes()
+	}
+
+	b, _ := json.Marshal(w.body.String()) //nolint:errcheck // String is always marshaled.
+
+	return b
+}
@@ -0,0 +1,139 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+)
+
+func TestService_Batch(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+
+	type getInput struct {
+		ID    int    `path:"id" minimum:"1"`
+		Token string `header:"X-Token"`
+	}
+
+	type itemOutput struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name,omitempty"`
+		Token string `json:"token,omitempty"`
+	}
+
+	s.Get("/items/{id}", usecase.NewInteractor(func(_ context.Context, in getInput, out *itemOutput) error {
+		out.ID = in.ID
+		out.Token = in.Token
+
+		return nil
+	}))
+
+	type postInput struct {
+		Name string `json:"name"`
+	}
+
+	s.Post("/items", usecase.NewInteractor(func(_ context.Context, in postInput, out *itemOutput) error {
+		out.ID = 100
+		out.Name = in.Name
+
+		return nil
+	}))
+
+	s.Batch("/batch", func(c *web.BatchConfig) {
+		c.MaxItems = 6
+		c.InheritHeaders = []string{"X-Token"}
+	})
+
+	serve := func(body string) (int, []byte) {
+		req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Token", "abc")
+
+		rw := httptest.NewRecorder()
+		s.ServeHTTP(rw, req)
+
+		return rw.Code, rw.Body.Bytes()
+	}
+
+	code, body := serve(`{"requests":[
+	  {"method":"GET","path":"/items/1"},
+	  {"method":"GET","path":"/items/2","headers":{"X-Token":"def"}},
+	  {"method":"GET","path":"/items/0"},
+	  {"method":"POST","path":"/items","body":{"name":"foo"}},
+	  {"method":"POST","path":"/batch#x","body":{"requests":[{"method":"GET","path":"/items/1"}]}},
+	  {"method":"POST","path":"/batch?x=1","body":{"requests":[{"method":"GET","path":"/items/1"}]}}
+	]}`)
+	assert.Equal(t, http.StatusOK, code, string(body))
+	assertjson.Equal(t, []byte(`{"responses":[
+	  {"status":200,"headers":{"Content-Type":["application/json"],"Content-Length":"<ignore-diff>"},"body":{"id":1,"token":"abc"}},
+	  {"status":200,"headers":"<ignore-diff>","body":{"id":2,"token":"def"}},
+	  {"status":400,"headers":"<ignore-diff>","body":{"status":"INVALID_ARGUMENT","error":"invalid argument: validation failed","context":"<ignore-diff>"}},
+	  {"status":200,"headers":"<ignore-diff>","body":{"id":100,"name":"foo"}},
+	  {"status":400,"headers":"<ignore-diff>","body":{"status":"INVALID_ARGUMENT","error":"invalid argument: nested batch is not allowed"}},
+	  {"status":400,"headers":"<ignore-diff>","body":{"status":"INVALID_ARGUMENT","error":"invalid argument: nested batch is not allowed"}}
+	]}`), body)
+
+	code, body = serve(`{"requests":[` + strings.Repeat(`{"method":"GET","path":"/items/1"},`, 6) +
+		`{"method":"GET","path":"/items/1"}]}`)
+	assert.Equal(t, http.StatusBadRequest, code, string(body))
+	assertjson.Equal(t, []byte(`{"status":"INVALID_ARGUMENT","error":"invalid argument: too many requests in batch"}`), body)
+
+	j, err := json.Marshal(s.OpenAPISchema())
+	require.NoError(t, err)
+	assert.Contains(t, string(j), `"/batch":{"post":{"summary":"Batch","description":"Serves multiple API requests in one round trip.","operationId":"batch"`)
+	assert.Contains(t, string(j), `"WebBatchRequest":{"required":["method","path"]`)
+}
+
+func TestService_Batch_panic(t *testing.T) {
+	var internalErr error
+
+	s := web.NewService(openapi3.NewReflector(), func(s *web.Service) {
+		s.HandleInternalError = func(_ *http.Request, err error) {
+			internalErr = err
+		}
+	})
+
+	// Router-level middleware is not covered by panic recovery of use case handlers.
+	s.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/panic" {
+				panic("failed")
+			}
+
+			next.ServeHTTP(rw, r)
+		})
+	})
+
+	s.Method(http.MethodGet, "/cookies", http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		http.SetCookie(rw, &http.Cookie{Name: "a", Value: "1"})
+		http.SetCookie(rw, &http.Cookie{Name: "b", Value: "2"})
+	}))
+
+	s.Batch("/batch")
+
+	req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(`{"requests":[
+	  {"method":"GET","path":"/panic"},
+	  {"method":"GET","path":"/cookies"}
+	]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code, rw.Body.String())
+	assertjson.Equal(t, []byte(`{"responses":[
+	  {"status":500,"headers":{"Content-Type":["application/json"]},"body":{"error":"internal error"}},
+	  {"status":200,"headers":{"Set-Cookie":["a=1","b=2"]}}
+	]}`), rw.Body.Bytes())
+	require.Error(t, internalErr)
+	assert.Contains(t, internalErr.Error(), "panic in GET /panic: failed")
+}
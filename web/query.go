@@ -0,0 +1,532 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/rest/request"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// QueryConfig controls aggregate query handler.
+type QueryConfig struct {
+	// MaxOperations limits number of operations in a query, default 20.
+	MaxOperations int
+
+	// Concurrency limits number of operations served at the same time, default 4.
+	Concurrency int
+
+	// InheritHeaders are names of headers to copy from query request to operation requests,
+	// for example "Authorization".
+	InheritHeaders []string
+}
+
+// QueryOperation describes a read operation of aggregate query.
+type QueryOperation struct {
+	Key       string                     `json:"key,omitempty" description:"Key of result, default is operation name."`
+	Operation string                     `json:"operation" required:"true" description:"Operation ID."`
+	Params    map[string]json.RawMessage `json:"params,omitempty" description:"Parameters by name or by location/name."`
+}
+
+// QueryResult describes a result of read operation of aggregate query.
+type QueryResult struct {
+	Status int             `json:"status" description:"HTTP status of operation."`
+	Data   json.RawMessage `json:"data,omitempty" description:"Successful response."`
+	Error  json.RawMessage `json:"error,omitempty" description:"Error response."`
+}
+
+type queryParam struct {
+	in       rest.ParamIn
+	name     string
+	key      string
+	schema   jsonschema.SchemaOrBool
+	required bool
+}
+
+type queryOperation struct {
+	name        string
+	description string
+	pattern     string
+
+	// params are keyed by location and name, for example "query/id".
+	params map[string]queryParam
+
+	// keys map parameter keys of query to location and name.
+	keys map[string]string
+}
+
+type queryOperations struct {
+	byName map[string]*queryOperation
+	names  []string
+}
+
+type queryInput struct {
+	request.EmbeddedSetter
+	Queries []QueryOperation `json:"queries"`
+
+	ops *queryOperations
+}
+
+type queryOutput struct {
+	Results map[string]QueryResult `json:"results"`
+}
+
+// JSONBodyMediaType implements rest.InputWithJSONBody.
+func (queryInput) JSONBodyMediaType() string {
+	return "application/json"
+}
+
+// JSONBodySchema implements rest.InputWithJSONBody.
+//
+// Each query item is described with parameters of operation.
+func (i queryInput) JSONBodySchema(_ *jsonschema.Reflector) (jsonschema.Schema, error) {
+	items := make([]jsonschema.SchemaOrBool, 0, len(i.ops.names))
+
+	for _, name := range i.ops.names {
+		op := i.ops.byName[name]
+
+		params := jsonschema.Schema{}
+		params.WithType(jsonschema.Object.Type())
+		params.WithAdditionalProperties(jsonschema.SchemaOrBool{TypeBoolean: new(bool)})
+
+		props := make(map[string]jsonschema.SchemaOrBool, len(op.params))
+
+		for _, p := range op.params {
+			props[p.key] = p.schema
+
+			if p.required {
+				params.Required = append(params.Required, p.key)
+			}
+		}
+
+		sort.Strings(params.Required)
+		params.WithProperties(props)
+
+		key := jsonschema.Schema{}
+		key.WithType(jsonschema.String.Type())
+
+		operation := jsonschema.Schema{}
+		operation.WithType(jsonschema.String.Type())
+		operation.WithEnum(name)
+
+		item := jsonschema.Schema{}
+		item.WithType(jsonschema.Object.Type())
+		item.WithRequired("operation")
+
+		if len(params.Required) > 0 {
+			item.WithRequired("operation", "params")
+		}
+
+		item.WithProperties(map[string]jsonschema.SchemaOrBool{
+			"key":       key.ToSchemaOrBool(),
+			"operation": operation.ToSchemaOrBool(),
+			"params":    params.ToSchemaOrBool(),
+		})
+
+		if op.description != "" {
+			item.WithDescription(op.description)
+		}
+
+		items = append(items, item.ToSchemaOrBool())
+	}
+
+	queryItems := jsonschema.Schema{}
+	queryItems.WithType(jsonschema.Object.Type())
+	queryItems.WithOneOf(items...)
+
+	queries := jsonschema.Schema{}
+	queries.WithType(jsonschema.Array.Type())
+	queries.WithMinItems(1)
+	queries.WithItems(*(&jsonschema.Items{}).WithSchemaOrBool(queryItems.ToSchemaOrBool()))
+
+	body := jsonschema.Schema{}
+	body.WithType(jsonschema.Object.Type())
+	body.WithRequired("queries")
+	body.WithProperties(map[string]jsonschema.SchemaOrBool{
+		"queries": queries.ToSchemaOrBool(),
+	})
+
+	return body, nil
+}
+
+// Query adds the route `pattern` that matches a POST http method to serve multiple read operations at once.
+//
+// Named use cases that are added with GET method before Query is called are available as operations.
+// Operation parameters are given as JSON values by names, names that are used in multiple locations
+// are prefixed with location, for example "query/id". Parameters are mapped to path, query, header or cookie
+// parameters of request that is served in-process by service router with all middlewares, so that
+// decoding and validation of parameters is the same as in direct request.
+// Operations with object parameters (for example deepObject query) are not supported.
+// Results are keyed by query keys.
+func (s *Service) Query(pattern string, options ...func(c *QueryConfig)) {
+	c := QueryConfig{
+		MaxOperations: 20,
+		Concurrency:   4,
+	}
+
+	for _, o := range options {
+		o(&c)
+	}
+
+	ops, err := s.queryOperations()
+	if err != nil {
+		panic(err)
+	}
+
+	interact := func(ctx context.Context, input, output interface{}) error {
+		var (
+			in  = input.(queryInput)    //nolint:errcheck // Type is ensured by input port.
+			out = output.(*queryOutput) //nolint:errcheck // Type is ensured by output port.
+		)
+
+		if len(in.Queries) > c.MaxOperations {
+			return status.Wrap(errors.New("too many operations in query"), status.InvalidArgument)
+		}
+
+		keys := make(map[string]bool, len(in.Queries))
+
+		for i, q := range in.Queries {
+			if q.Key == "" {
+				in.Queries[i].Key = q.Operation
+			}
+
+			if keys[in.Queries[i].Key] {
+				return status.Wrap(fmt.Errorf("duplicate query key: %s", in.Queries[i].Key), status.InvalidArgument)
+			}
+
+			keys[in.Queries[i].Key] = true
+		}
+
+		var (
+			wg  sync.WaitGroup
+			mu  sync.Mutex
+			sem = make(chan struct{}, c.Concurrency)
+		)
+
+		out.Results = make(map[string]QueryResult, len(in.Queries))
+
+		for _, q := range in.Queries {
+			q := q
+
+			wg.Add(1)
+
+			sem <- struct{}{}
+
+			go func() {
+				var res QueryResult
+
+				defer func() {
+					if rec := recover(); rec != nil {
+						s.handlePanic(in.Request(), fmt.Errorf("panic in query operation %s: %v\n%s",
+							q.Operation, rec, debug.Stack()))
+
+						res = QueryResult{
+							Status: http.StatusInternalServerError,
+							Error:  json.RawMessage(`{"error":"internal error"}`),
+						}
+					}
+
+					mu.Lock()
+					out.Results[q.Key] = res
+					mu.Unlock()
+
+					<-sem
+					wg.Done()
+				}()
+
+				res = s.serveQueryOperation(ctx, c, in.Request(), ops.byName[q.Operation], q)
+			}()
+		}
+
+		wg.Wait()
+
+		return nil
+	}
+
+	u := usecase.NewIOI(queryInput{ops: ops}, new(queryOutput), interact)
+	u.SetName("query")
+	u.SetTitle("Query")
+	u.SetDescription("Serves multiple read operations in one round trip.")
+	u.SetExpectedErrors(status.InvalidArgument)
+
+	s.Post(pattern, u)
+}
+
+// queryOperations collects named GET use cases with their parameters.
+func (s *Service) queryOperations() (*queryOperations, error) {
+	ops := &queryOperations{byName: make(map[string]*queryOperation)}
+
+	err := chi.Walk(s.Wrapper, func(method, route string, h http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if method != http.MethodGet {
+			return nil
+		}
+
+		var handler *nethttp.Handler
+		if !nethttp.HandlerAs(h, &handler) {
+			return nil
+		}
+
+		var (
+			hasName        usecase.HasName
+			hasDescription usecase.HasDescription
+			hasTitle       usecase.HasTitle
+			withInput      usecase.HasInputPort
+		)
+
+		u := handler.UseCase()
+
+		if !usecase.As(u, &hasName) || hasName.Name() == "" {
+			return nil
+		}
+
+		op := &queryOperation{
+			name:    hasName.Name(),
+			pattern: route,
+			params:  make(map[string]queryParam),
+			keys:    make(map[string]string),
+		}
+
+		if usecase.As(u, &hasTitle) {
+			op.description = hasTitle.Title()
+		}
+
+		if usecase.As(u, &hasDescription) && hasDescription.Description() != "" {
+			op.description = strings.TrimSpace(op.description + "\n\n" + hasDescription.Description())
+		}
+
+		if usecase.As(u, &withInput) && withInput.InputPort() != nil {
+			if err := s.collectQueryParams(op, withInput.InputPort(), handler.ReqMapping); err != nil {
+				return fmt.Errorf("collect parameters of %s: %w", op.name, err)
+			}
+
+			op.setKeys()
+		}
+
+		if _, ok := ops.byName[op.name]; !ok {
+			ops.names = append(ops.names, op.name)
+		}
+
+		ops.byName[op.name] = op
+
+		return nil
+	})
+
+	sort.Strings(ops.names)
+
+	return ops, err
+}
+
+// collectQueryParams collects parameter schemas of operation input with documentation reflector.
+func (s *Service) collectQueryParams(op *queryOperation, input interface{}, mapping rest.RequestMapping) error {
+	cu := openapi.ContentUnit{}
+	cu.Structure = input
+
+	if mapping != nil {
+		for _, in := range []rest.ParamIn{rest.ParamInPath, rest.ParamInQuery, rest.ParamInHeader, rest.ParamInCookie} {
+			cu.SetFieldMapping(openapi.In(in), mapping[in])
+		}
+	}
+
+	return s.OpenAPIReflector().WalkRequestJSONSchemas(http.MethodGet, cu,
+		func(in openapi.In, paramName string, schema *jsonschema.SchemaOrBool, required bool) error {
+			p := queryParam{in: rest.ParamIn(in), name: paramName, required: required}
+
+			if schema != nil && schema.TypeObject != nil && schema.TypeObject.HasType(jsonschema.Object) {
+				return errors.New("object type is not supported")
+			}
+
+			if schema != nil {
+				p.schema = *schema
+			} else {
+				p.schema.WithTypeBoolean(true)
+			}
+
+			op.params[string(p.in)+"/"+paramName] = p
+
+			return nil
+		}, nil)
+}
+
+// setKeys assigns query keys to parameters, names that are used in multiple locations are prefixed with location.
+func (op *queryOperation) setKeys() {
+	count := make(map[string]int, len(op.params))
+
+	for _, p := range op.params {
+		count[p.name]++
+	}
+
+	for id, p := range op.params {
+		p.key = p.name
+		if count[p.name] > 1 {
+			p.key = id
+		}
+
+		op.params[id] = p
+		op.keys[p.key] = id
+	}
+}
+
+var pathParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?}`)
+
+func (s *Service) serveQueryOperation(
+	ctx context.Context,
+	c QueryConfig,
+	parent *http.Request,
+	op *queryOperation,
+	q QueryOperation,
+) QueryResult {
+	if op == nil {
+		return queryError(status.Wrap(fmt.Errorf("unknown operation: %s", q.Operation), status.InvalidArgument))
+	}
+
+	values := make(map[string][]string, len(q.Params))
+
+	for key, v := range q.Params {
+		id, ok := op.keys[key]
+		if !ok {
+			return queryError(status.Wrap(fmt.Errorf("unknown parameter: %s", key), status.InvalidArgument))
+		}
+
+		vv, err := paramValues(v)
+		if err != nil {
+			return queryError(status.Wrap(fmt.Errorf("parameter %s: %w", key, err), status.InvalidArgument))
+		}
+
+		values[id] = vv
+	}
+
+	var missing []string
+
+	path := pathParam.ReplaceAllStringFunc(op.pattern, func(p string) string {
+		name := pathParam.FindStringSubmatch(p)[1]
+
+		if v := values[string(rest.ParamInPath)+"/"+name]; len(v) > 0 {
+			return url.PathEscape(v[0])
+		}
+
+		missing = append(missing, name)
+
+		return p
+	})
+
+	if len(missing) > 0 {
+		return queryError(status.Wrap(fmt.Errorf("missing path parameters: %s", strings.Join(missing, ", ")),
+			status.InvalidArgument))
+	}
+
+	query := url.Values{}
+
+	for id, p := range op.params {
+		if p.in == rest.ParamInQuery && len(values[id]) > 0 {
+			query[p.name] = values[id]
+		}
+	}
+
+	target := strings.TrimSuffix(path, "/*")
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	r, err := newSubRequest(ctx, http.MethodGet, target, bytes.NewReader(nil))
+	if err != nil {
+		return queryError(status.Wrap(err, status.InvalidArgument))
+	}
+
+	inheritHeaders(parent, r, c.InheritHeaders)
+
+	for id, p := range op.params {
+		for _, v := range values[id] {
+			switch p.in { //nolint:exhaustive // Other locations are handled above.
+			case rest.ParamInHeader:
+				r.Header.Add(p.name, v)
+			case rest.ParamInCookie:
+				r.AddCookie(&http.Cookie{Name: p.name, Value: v})
+			}
+		}
+	}
+
+	rw := s.serveSubRequest(r)
+	res := QueryResult{Status: rw.status}
+
+	if rw.status >= 200 && rw.status < 300 {
+		res.Data = rw.jsonBody()
+	} else {
+		res.Error = rw.jsonBody()
+	}
+
+	return res
+}
+
+// paramValues converts JSON value to string values of parameter.
+func paramValues(v json.RawMessage) ([]string, error) {
+	d := json.NewDecoder(bytes.NewReader(v))
+	d.UseNumber()
+
+	var val interface{}
+	if err := d.Decode(&val); err != nil {
+		return nil, err
+	}
+
+	if items, ok := val.([]interface{}); ok {
+		res := make([]string, 0, len(items))
+
+		for _, item := range items {
+			s, err := scalarString(item)
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, s)
+		}
+
+		return res, nil
+	}
+
+	if val == nil {
+		return nil, nil
+	}
+
+	s, err := scalarString(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{s}, nil
+}
+
+func scalarString(v interface{}) (string, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case json.Number:
+		return s.String(), nil
+	case bool:
+		return strconv.FormatBool(s), nil
+	default:
+		return "", errors.New("scalar value or array of scalar values expected")
+	}
+}
+
+func queryError(err error) QueryResult {
+	code, er := rest.Err(err)
+	body, _ := json.Marshal(er) //nolint:errcheck // ErrResponse is always marshaled.
+
+	return QueryResult{Status: code, Error: body}
+}
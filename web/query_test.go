@@ -0,0 +1,245 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+func TestService_Query(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+
+	type itemInput struct {
+		ID     int      `path:"id" minimum:"1"`
+		Fields []string `query:"fields"`
+		Locale string   `header:"X-Locale"`
+	}
+
+	type itemOutput struct {
+		ID     int      `json:"id"`
+		Fields []string `json:"fields,omitempty"`
+		Locale string   `json:"locale,omitempty"`
+	}
+
+	getItem := usecase.NewInteractor(func(_ context.Context, in itemInput, out *itemOutput) error {
+		if in.ID == 404 {
+			return status.NotFound
+		}
+
+		out.ID = in.ID
+		out.Fields = in.Fields
+		out.Locale = in.Locale
+
+		return nil
+	})
+	getItem.SetName("getItem")
+	getItem.SetTitle("Get item")
+
+	type listInput struct {
+		Limit int `query:"limit" default:"10" maximum:"100"`
+	}
+
+	listItems := usecase.NewInteractor(func(_ context.Context, in listInput, out *[]int) error {
+		for i := 1; i <= in.Limit; i++ {
+			*out = append(*out, i)
+		}
+
+		return nil
+	})
+	listItems.SetName("listItems")
+
+	s.Get("/items/{id}", getItem)
+	s.Get("/items", listItems)
+	s.Post("/items", usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error {
+		return nil
+	}))
+
+	s.Query("/query", func(c *web.QueryConfig) {
+		c.InheritHeaders = []string{"X-Locale"}
+	})
+
+	serve := func(body string) (int, []byte) {
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Locale", "en")
+
+		rw := httptest.NewRecorder()
+		s.ServeHTTP(rw, req)
+
+		return rw.Code, rw.Body.Bytes()
+	}
+
+	code, body := serve(`{"queries":[
+	  {"operation":"getItem","params":{"id":1,"fields":["a","b"]}},
+	  {"key":"missing","operation":"getItem","params":{"id":404}},
+	  {"operation":"listItems","params":{"limit":3}},
+	  {"key":"defaults","operation":"listItems"}
+	]}`)
+	assert.Equal(t, http.StatusOK, code, string(body))
+	assertjson.Equal(t, []byte(`{"results":{
+	  "getItem":{"status":200,"data":{"id":1,"fields":["a","b"],"locale":"en"}},
+	  "missing":{"status":404,"error":{"status":"NOT_FOUND","error":"not found"}},
+	  "listItems":{"status":200,"data":[1,2,3]},
+	  "defaults":{"status":200,"data":[1,2,3,4,5,6,7,8,9,10]}
+	}}`), body)
+
+	// Parameters are validated with schemas of operations.
+	code, body = serve(`{"queries":[{"operation":"getItem","params":{"id":0}}]}`)
+	assert.Equal(t, http.StatusBadRequest, code, string(body))
+	assert.Contains(t, string(body), `#/queries/0/params/id: must be >= 1/1 but found 0`)
+
+	code, body = serve(`{"queries":[{"operation":"getItem"}]}`)
+	assert.Equal(t, http.StatusBadRequest, code, string(body))
+	assert.Contains(t, string(body), `#/queries/0: missing properties: \"params\"`)
+
+	code, body = serve(`{"queries":[{"operation":"listItems","params":{"limit":1}},{"operation":"listItems"}]}`)
+	assert.Equal(t, http.StatusBadRequest, code, string(body))
+	assertjson.Equal(t, []byte(`{"status":"INVALID_ARGUMENT","error":"invalid argument: duplicate query key: listItems"}`), body)
+
+	code, body = serve(`{"queries":[{"operation":"listItems","params":{"offset":1}}]}`)
+	assert.Equal(t, http.StatusBadRequest, code, string(body))
+
+	code, body = serve(`{"queries":[{"operation":"createItem"}]}`)
+	assert.Equal(t, http.StatusBadRequest, code, string(body))
+
+	j, err := json.Marshal(s.OpenAPISchema())
+	require.NoError(t, err)
+
+	var spec struct {
+		Paths map[string]map[string]struct {
+			RequestBody json.RawMessage `json:"requestBody"`
+		} `json:"paths"`
+	}
+
+	require.NoError(t, json.Unmarshal(j, &spec))
+	assertjson.Equal(t, []byte(`{
+	  "content":{"application/json":{"schema":{
+		"required":["queries"],
+		"properties":{"queries":{"minItems":1,"type":"array","items":{"type":"object","oneOf":[
+		  {
+			"description":"Get item","required":["operation","params"],"type":"object",
+			"properties":{
+			  "key":{"type":"string"},"operation":{"enum":["getItem"],"type":"string"},
+			  "params":{
+				"required":["id"],"additionalProperties":false,"type":"object",
+				"properties":{
+				  "id":{"minimum":1,"type":"integer"},
+				  "fields":{"items":{"type":"string"},"type":"array"},
+				  "X-Locale":{"type":"string"}
+				}
+			  }
+			}
+		  },
+		  {
+			"required":["operation"],"type":"object","description":"<ignore-diff>",
+			"properties":{
+			  "key":{"type":"string"},"operation":{"enum":["listItems"],"type":"string"},
+			  "params":{
+				"additionalProperties":false,"type":"object",
+				"properties":{"limit":{"default":10,"maximum":100,"type":"integer"}}
+			  }
+			}
+		  }
+		]}}},
+		"type":"object"
+	  }}},
+	  "required":true
+	}`), spec.Paths["/query"]["post"].RequestBody)
+}
+
+func TestService_Query_sameName(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+
+	type input struct {
+		PathID  string `path:"id"`
+		QueryID string `query:"id"`
+		Limit   int    `query:"limit"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, in input, out *[]string) error {
+		*out = []string{in.PathID, in.QueryID, strconv.Itoa(in.Limit)}
+
+		return nil
+	})
+	u.SetName("getItem")
+
+	s.Get("/items/{id}", u)
+	s.Query("/query")
+
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"queries":[
+	  {"operation":"getItem","params":{"path/id":"p","query/id":"q","limit":2}}
+	]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code, rw.Body.String())
+	assertjson.Equal(t, []byte(`{"results":{"getItem":{"status":200,"data":["p","q","2"]}}}`), rw.Body.Bytes())
+}
+
+func TestService_Query_panic(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector(), func(s *web.Service) {
+		s.HandleInternalError = func(_ *http.Request, _ error) {}
+	})
+
+	s.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/items" {
+				panic("failed")
+			}
+
+			next.ServeHTTP(rw, r)
+		})
+	})
+
+	u := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *[]int) error {
+		return nil
+	})
+	u.SetName("listItems")
+
+	s.Get("/items", u)
+	s.Query("/query")
+
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"queries":[{"operation":"listItems"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rw := httptest.NewRecorder()
+	s.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code, rw.Body.String())
+	assertjson.Equal(t, []byte(`{"results":{"listItems":{"status":500,"error":{"error":"internal error"}}}}`),
+		rw.Body.Bytes())
+}
+
+func TestService_Query_objectParam(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+
+	type input struct {
+		Filter map[string]string `query:"filter"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, _ input, _ *[]int) error {
+		return nil
+	})
+	u.SetName("listItems")
+
+	s.Get("/items", u)
+
+	assert.PanicsWithError(t, "collect parameters of listItems: "+
+		"schema for parameter (query, filter): object type is not supported", func() {
+		s.Query("/query")
+	})
+}
@@ -11,11 +11,16 @@ import (
 	"github.com/swaggest/openapi-go/openapi3"
 	"github.com/swaggest/rest"
 	"github.com/swaggest/rest/chirouter"
+	"github.com/swaggest/rest/inventory"
+	"github.com/swaggest/rest/jobs"
 	"github.com/swaggest/rest/jsonschema"
+	"github.com/swaggest/rest/lint"
+	"github.com/swaggest/rest/metrics"
 	"github.com/swaggest/rest/nethttp"
 	"github.com/swaggest/rest/openapi"
 	"github.com/swaggest/rest/request"
 	"github.com/swaggest/rest/response"
+	"github.com/swaggest/rest/webhook"
 	"github.com/swaggest/usecase"
 )
 
@@ -41,6 +46,20 @@ func NewService(refl oapi.Reflector, options ...func(s *Service)) *Service {
 		s.Wrapper = chirouter.NewWrapper(chi.NewRouter())
 	}
 
+	if s.CORS != nil {
+		s.Wrapper.EnableCORS(*s.CORS)
+	}
+
+	if s.AutoOptions {
+		s.Wrapper.EnableAutoOptions()
+	}
+
+	if s.Jobs != nil {
+		s.Wrapper.EnableAsync(func(r chi.Router, method, pattern string, h http.Handler) {
+			s.Jobs.Method(r, method, pattern, h)
+		})
+	}
+
 	if s.DecoderFactory == nil {
 		decoderFactory := request.NewDecoderFactory()
 		decoderFactory.ApplyDefaults = true
@@ -57,6 +76,20 @@ func NewService(refl oapi.Reflector, options ...func(s *Service)) *Service {
 		s.PanicRecoveryMiddleware = middleware.Recoverer
 	}
 
+	if s.AccessLog != nil {
+		s.Wrap(nethttp.AccessLogMiddleware(s.AccessLog))
+	}
+
+	if s.HandleInternalError != nil {
+		s.Wrap(nethttp.OptionsMiddleware(func(h *nethttp.Handler) {
+			h.HandleInternalError = s.HandleInternalError
+		}))
+	}
+
+	if s.Metrics != nil {
+		s.Wrap(s.Metrics.Middleware)
+	}
+
 	// Setup middlewares.
 	s.Wrap(
 		s.PanicRecoveryMiddleware,                     // Panic recovery.
@@ -66,6 +99,10 @@ func NewService(refl oapi.Reflector, options ...func(s *Service)) *Service {
 		response.EncoderMiddleware,                    // Response encoder setup.
 	)
 
+	if s.AutoOptions {
+		s.enableAutoOptions()
+	}
+
 	return &s
 }
 
@@ -111,6 +148,33 @@ type Service struct {
 
 	// AddHeadToGet is an option to enable HEAD method for each usecase added with Service.Get.
 	AddHeadToGet bool
+
+	// CORS enables Cross-Origin Resource Sharing derived from registered routes, if set with NewService option.
+	CORS *chirouter.CORS
+
+	// AutoOptions enables automatic responses to OPTIONS requests and 405 error responses
+	// with Allow header computed from registered routes, if set with NewService option.
+	AutoOptions bool
+
+	// DocumentAutoOptions adds automatic OPTIONS operations and 405 responses to API documentation.
+	DocumentAutoOptions bool
+
+	// AccessLog is called after each request served by use case handler, if set with NewService option.
+	AccessLog func(r *http.Request, l nethttp.AccessLog)
+
+	// HandleInternalError receives failures that can not be served with response, if set with NewService option.
+	// It can also be used with gzip.MiddlewareWithErrorHandler.
+	HandleInternalError func(r *http.Request, err error)
+
+	// Metrics collects request metrics of use case handlers, if set with NewService option.
+	// It can be mounted to expose metrics, e.g. s.Method(http.MethodGet, "/metrics", s.Metrics).
+	Metrics *metrics.Collector
+
+	// Jobs serves use cases marked with jobs.Async in background, if set with NewService option.
+	// Adding async use case to a service without Jobs panics.
+	Jobs *jobs.Manager
+
+	autoOptionsPatterns map[string]bool
 }
 
 // OpenAPISchema returns OpenAPI schema.
@@ -125,6 +189,21 @@ func (s *Service) OpenAPIReflector() oapi.Reflector {
 	return s.OpenAPICollector.Refl()
 }
 
+// Check reports misconfiguration of added use case handlers.
+//
+// Use findings.Err() in a test to fail on problems of Error severity.
+func (s *Service) Check() (lint.Findings, error) {
+	return lint.CheckRoutes(s.Wrapper, s.OpenAPICollector)
+}
+
+// RouteInventory lists added routes with their use cases, middlewares, security and decoders.
+//
+// Inventory can be served for operations with a debug handler, e.g.
+// s.Method(http.MethodGet, "/debug/routes", inventory.NewHandler(s, s.OpenAPICollector)).
+func (s *Service) RouteInventory() ([]inventory.Route, error) {
+	return inventory.Collect(s.Wrapper, s.OpenAPICollector)
+}
+
 // Delete adds the route `pattern` that matches a DELETE http method to invoke use case interactor.
 func (s *Service) Delete(pattern string, uc usecase.Interactor, options ...func(h *nethttp.Handler)) {
 	s.Method(http.MethodDelete, pattern, nethttp.NewHandler(uc, options...))
@@ -193,6 +272,21 @@ func (s *Service) OnMethodNotAllowed(uc usecase.Interactor, options ...func(h *n
 	s.MethodNotAllowed(s.HandlerFunc(nethttp.NewHandler(uc, options...)))
 }
 
+// Webhook adds OpenAPI 3.1 webhook to documentation.
+func (s *Service) Webhook(w webhook.Webhook) {
+	if err := s.OpenAPICollector.AddWebhook(w); err != nil {
+		panic(err)
+	}
+}
+
+// Callback documents webhook as a callback of operation.
+//
+// It must be called before operation is added, expression is a runtime expression of callback URL,
+// for example "{$request.body#/callbackUrl}".
+func (s *Service) Callback(method, pattern, expression string, w webhook.Webhook) {
+	s.OpenAPICollector.AddCallback(method, pattern, expression, w)
+}
+
 // Docs adds the route `pattern` that serves API documentation with Swagger UI.
 //
 // Swagger UI should be provided by `swgui` handler constructor, you can use one of these functions
@@ -208,8 +302,11 @@ func (s *Service) OnMethodNotAllowed(uc usecase.Interactor, options ...func(h *n
 //	github.com/swaggest/swgui/v3.New
 //
 // or create your own.
+//
+// OpenAPI document is also available in YAML format at `pattern`/openapi.yaml.
 func (s *Service) Docs(pattern string, swgui func(title, schemaURL, basePath string) http.Handler) {
 	pattern = strings.TrimRight(pattern, "/")
 	s.Method(http.MethodGet, pattern+"/openapi.json", s.OpenAPICollector)
+	s.Method(http.MethodGet, pattern+"/openapi.yaml", s.OpenAPICollector)
 	s.Mount(pattern, swgui(s.OpenAPISchema().Title(), pattern+"/openapi.json", pattern))
 }
@@ -2,6 +2,7 @@ package web_test
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -92,3 +93,21 @@ func TestDefaultService(t *testing.T) {
 
 	assert.Equal(t, []string{"one", "two"}, l)
 }
+
+func TestService_Handle_strictOperationIDs(t *testing.T) {
+	s := web.NewService(openapi3.NewReflector())
+	s.OpenAPICollector.StrictOperationIDs = true
+
+	u := usecase.NewInteractor(func(_ context.Context, _ struct{}, _ *struct{}) error { return nil })
+	u.SetName("thing")
+
+	require.NotPanics(t, func() {
+		s.Handle("/a", nethttp.NewHandler(u))
+	})
+
+	j, err := json.Marshal(s.OpenAPISchema())
+	require.NoError(t, err)
+	assert.Contains(t, string(j), `"operationId":"thing"`)
+	assert.Contains(t, string(j), `"operationId":"thingPost"`)
+	assert.Contains(t, string(j), `"operationId":"thingDelete"`)
+}
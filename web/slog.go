@@ -0,0 +1,56 @@
+//go:build go1.21
+
+package web
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/swaggest/rest/nethttp"
+)
+
+// WithLogger is a NewService option to enable structured access and error logging with slog.Logger.
+//
+// Requests served with 5xx status are logged with error level, other requests are logged with info level.
+// Input is logged as slog.LogValuer if it implements one, otherwise as nethttp.AccessLog.InputSummary.
+func WithLogger(logger *slog.Logger) func(s *Service) {
+	return func(s *Service) {
+		s.AccessLog = func(r *http.Request, l nethttp.AccessLog) {
+			level := slog.LevelInfo
+			if l.Status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			attrs := []slog.Attr{
+				slog.String("operation", l.OperationID),
+				slog.String("method", r.Method),
+				slog.String("route", l.Pattern),
+				slog.Int("status", l.Status),
+				slog.Duration("latency", l.Latency),
+			}
+
+			if lv, ok := l.Input.(slog.LogValuer); ok {
+				attrs = append(attrs, slog.Any("input", lv))
+			} else if summary := l.InputSummary(); len(summary) > 0 {
+				attrs = append(attrs, slog.Any("input", summary))
+			}
+
+			if l.Err != nil {
+				attrs = append(attrs,
+					slog.String("error", l.Err.Error()),
+					slog.Any("errorChain", l.ErrorChain()),
+				)
+			}
+
+			logger.LogAttrs(r.Context(), level, "request served", attrs...)
+		}
+
+		s.HandleInternalError = func(r *http.Request, err error) {
+			logger.LogAttrs(r.Context(), slog.LevelError, "internal failure",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+//go:build go1.21
+
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest/web"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+func TestWithLogger(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "latency" {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+
+	s := web.NewService(openapi3.NewReflector(), web.WithLogger(logger))
+
+	type loginInput struct {
+		Login    string `json:"login" minLength:"3"`
+		Password string `json:"password" redact:"true"`
+	}
+
+	u := usecase.NewInteractor(func(_ context.Context, in loginInput, _ *struct{}) error {
+		if in.Login == "admin" {
+			return status.Wrap(errors.New("admin is not allowed"), status.PermissionDenied)
+		}
+
+		return nil
+	})
+	u.SetName("login")
+
+	s.Post("/login", u)
+
+	for _, body := range []string{
+		`{"login":"john","password":"secret"}`,
+		`{"login":"admin","password":"secret"}`,
+		`{"login":"jo","password":"secret"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rw := httptest.NewRecorder()
+		s.ServeHTTP(rw, req)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.NotContains(t, buf.String(), "secret")
+
+	assertjson.Equal(t, []byte(`{
+	  "level":"INFO","msg":"request served","operation":"login","method":"POST","route":"/login","status":204,
+	  "input":{"login":"john","password":"[REDACTED]"}
+	}`), []byte(lines[0]))
+
+	assertjson.Equal(t, []byte(`{
+	  "level":"INFO","msg":"request served","operation":"login","method":"POST","route":"/login","status":403,
+	  "input":{"login":"admin","password":"[REDACTED]"},
+	  "error":"permission denied: admin is not allowed",
+	  "errorChain":["permission denied: admin is not allowed","admin is not allowed"]
+	}`), []byte(lines[1]))
+
+	assertjson.Equal(t, []byte(`{
+	  "level":"INFO","msg":"request served","operation":"login","method":"POST","route":"/login","status":400,
+	  "input":{"login":"jo","password":"[REDACTED]"},
+	  "error":"invalid argument: validation failed",
+	  "errorChain":"<ignore-diff>"
+	}`), []byte(lines[2]))
+}
@@ -0,0 +1,345 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swaggest/refl"
+	"github.com/swaggest/rest"
+)
+
+// Signature headers, as defined by Standard Webhooks specification.
+const (
+	HeaderID        = "Webhook-Id"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+)
+
+// DefaultTolerance is a default maximum difference between webhook timestamp and current time.
+const DefaultTolerance = 5 * time.Minute
+
+// DefaultTimeout is a default time limit of a single delivery attempt.
+const DefaultTimeout = 10 * time.Second
+
+// ErrUnknownWebhook is returned when sending a webhook that was not added to Dispatcher.
+var ErrUnknownWebhook = errors.New("unknown webhook")
+
+// DeliveryError describes failed delivery.
+type DeliveryError struct {
+	// StatusCode is an HTTP status of last response, 0 if there was no response.
+	StatusCode int
+
+	// Attempts is a number of delivery attempts.
+	Attempts int
+
+	// Err is an error of last attempt.
+	Err error
+}
+
+// Error implements error.
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("webhook delivery failed after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns error of last attempt.
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// NewDispatcher creates webhook dispatcher.
+func NewDispatcher(options ...func(d *Dispatcher)) *Dispatcher {
+	d := Dispatcher{
+		MaxAttempts: 3,
+		Backoff: func(attempt int) time.Duration {
+			return time.Duration(1<<uint(attempt-1)) * 100 * time.Millisecond
+		},
+		webhooks: make(map[string]registeredWebhook),
+	}
+
+	for _, o := range options {
+		o(&d)
+	}
+
+	if d.Client == nil {
+		d.Client = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return &d
+}
+
+// Dispatcher sends signed webhook requests and retries failed deliveries.
+//
+// Please use NewDispatcher to create instance.
+type Dispatcher struct {
+	// Client sends requests, default is a client with DefaultTimeout.
+	Client *http.Client
+
+	// Secret is a key to sign requests with HMAC-SHA256, requests are not signed if empty.
+	Secret []byte
+
+	// Validator makes validators of payloads, for example jsonschema.NewFactory(collector, collector).
+	// Payloads are not validated if Validator is nil.
+	Validator rest.RequestValidatorFactory
+
+	// MaxAttempts limits number of delivery attempts, default 3.
+	MaxAttempts int
+
+	// Backoff returns delay before next attempt, default is exponential starting with 100ms.
+	// Delay from Retry-After header of 429 and 503 responses takes precedence.
+	Backoff func(attempt int) time.Duration
+
+	mu       sync.Mutex
+	webhooks map[string]registeredWebhook
+}
+
+type registeredWebhook struct {
+	Webhook
+	validator rest.Validator
+}
+
+// Add registers webhook to be sent.
+func (d *Dispatcher) Add(w Webhook) {
+	rw := registeredWebhook{Webhook: w}
+
+	if d.Validator != nil && w.Payload != nil {
+		rw.validator = d.Validator.MakeRequestValidator(w.HTTPMethod(), w.Payload, nil)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.webhooks[w.Name] = rw
+}
+
+// Send validates payload and delivers webhook to URL.
+//
+// Deliveries that fail with network error, 429 Too Many Requests or 5xx status are retried.
+func (d *Dispatcher) Send(ctx context.Context, url, name string, payload interface{}) error {
+	d.mu.Lock()
+	w, found := d.webhooks[name]
+	d.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("%w: %s", ErrUnknownWebhook, name)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	headers := payloadHeaders(payload)
+
+	if w.validator != nil {
+		if err := w.validator.ValidateJSONBody(body); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+
+		goValues := make(map[string]interface{}, len(headers))
+		for k, v := range headers {
+			goValues[k] = v
+		}
+
+		if err := w.validator.ValidateData(rest.ParamInHeader, goValues); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+	}
+
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+
+	var (
+		lastErr    error
+		statusCode int
+		retryAfter time.Duration
+	)
+
+	for attempt := 1; attempt <= d.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			delay := retryAfter
+			if delay < 0 {
+				delay = d.Backoff(attempt - 1)
+			}
+
+			select {
+			case <-ctx.Done():
+				return &DeliveryError{StatusCode: statusCode, Attempts: attempt - 1, Err: ctx.Err()}
+			case <-time.After(delay):
+			}
+		}
+
+		var retry bool
+
+		statusCode, retryAfter, retry, lastErr = d.deliver(ctx, w.HTTPMethod(), url, id, body, headers)
+		if lastErr == nil {
+			return nil
+		}
+
+		if !retry {
+			return &DeliveryError{StatusCode: statusCode, Attempts: attempt, Err: lastErr}
+		}
+	}
+
+	return &DeliveryError{StatusCode: statusCode, Attempts: d.MaxAttempts, Err: lastErr}
+}
+
+// deliver makes a single delivery attempt, retryAfter is negative if response has no valid Retry-After.
+func (d *Dispatcher) deliver(
+	ctx context.Context,
+	method, url, id string,
+	body []byte,
+	headers map[string]string,
+) (statusCode int, retryAfter time.Duration, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, -1, false, err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set(HeaderID, id)
+	req.Header.Set(HeaderTimestamp, ts)
+
+	if len(d.Secret) > 0 {
+		req.Header.Set(HeaderSignature, Sign(d.Secret, id, ts, body))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, -1, ctx.Err() == nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close() //nolint:errcheck // Body is drained.
+	}()
+
+	_, _ = io.Copy(io.Discard, resp.Body) //nolint:errcheck // Body is drained to reuse connection.
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, -1, false, nil
+	}
+
+	err = fmt.Errorf("unexpected response status: %s", resp.Status)
+	retryAfter = -1
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+
+	return resp.StatusCode, retryAfter, resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// parseRetryAfter returns delay from Retry-After header value in seconds or HTTP-date form,
+// or -1 if value is missing or invalid.
+func parseRetryAfter(v string) time.Duration {
+	if sec, err := strconv.Atoi(v); err == nil && sec >= 0 {
+		return time.Duration(sec) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+
+		return 0
+	}
+
+	return -1
+}
+
+// Sign returns signature of webhook request.
+func Sign(secret []byte, id, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(body)
+
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks signature and timestamp of received webhook request.
+//
+// Request is rejected if its timestamp differs from current time by more than tolerance
+// to prevent replay attacks, DefaultTolerance is used if tolerance is zero.
+func Verify(secret []byte, h http.Header, body []byte, tolerance time.Duration) error {
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+
+	ts, err := strconv.ParseInt(h.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return errors.New("invalid webhook timestamp")
+	}
+
+	if d := time.Since(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+		return errors.New("webhook timestamp is out of tolerance")
+	}
+
+	expected := Sign(secret, h.Get(HeaderID), h.Get(HeaderTimestamp), body)
+
+	for _, s := range strings.Fields(h.Get(HeaderSignature)) {
+		if hmac.Equal([]byte(s), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return errors.New("invalid webhook signature")
+}
+
+// payloadHeaders collects non-empty values of fields with `header` tag.
+func payloadHeaders(payload interface{}) map[string]string {
+	headers := make(map[string]string)
+
+	v := reflect.ValueOf(payload)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return headers
+	}
+
+	refl.WalkTaggedFields(v, func(fv reflect.Value, _ reflect.StructField, tag string) {
+		name := strings.Split(tag, ",")[0]
+
+		if name == "" || fv.IsZero() {
+			return
+		}
+
+		headers[name] = fmt.Sprint(fv.Interface())
+	}, "header")
+
+	return headers
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return "msg_" + hex.EncodeToString(b), nil
+}
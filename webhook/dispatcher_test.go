@@ -0,0 +1,146 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/rest/jsonschema"
+	"github.com/swaggest/rest/openapi"
+	"github.com/swaggest/rest/webhook"
+)
+
+type taskFinished struct {
+	Event  string `header:"X-Event" json:"-" required:"true"`
+	TaskID int    `json:"taskId" required:"true" minimum:"1"`
+}
+
+func TestDispatcher_Send(t *testing.T) {
+	c := openapi.NewCollector(openapi31.NewReflector())
+	secret := []byte("s3cr3t")
+
+	d := webhook.NewDispatcher(func(d *webhook.Dispatcher) {
+		d.Secret = secret
+		d.Validator = jsonschema.NewFactory(c, c)
+		d.Backoff = func(int) time.Duration { return time.Millisecond }
+	})
+
+	w := webhook.Webhook{Name: "taskFinished", Payload: taskFinished{}}
+	require.NoError(t, c.AddWebhook(w))
+	d.Add(w)
+
+	var (
+		calls  int64
+		status int64 = http.StatusInternalServerError
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		assert.NoError(t, webhook.Verify(secret, r.Header, body, 0))
+		assert.Error(t, webhook.Verify([]byte("wrong"), r.Header, body, 0))
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "task.finished", r.Header.Get("X-Event"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, `{"taskId":123}`, string(body))
+
+		rw.WriteHeader(int(atomic.SwapInt64(&status, http.StatusNoContent)))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	require.NoError(t, d.Send(ctx, srv.URL, "taskFinished", taskFinished{Event: "task.finished", TaskID: 123}))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&calls))
+
+	err := d.Send(ctx, srv.URL, "taskFinished", taskFinished{Event: "task.finished"})
+	assert.EqualError(t, err, "invalid payload: validation failed")
+
+	err = d.Send(ctx, srv.URL, "taskFinished", taskFinished{TaskID: 1})
+	assert.EqualError(t, err, "invalid payload: validation failed")
+
+	err = d.Send(ctx, srv.URL, "unknown", taskFinished{})
+	assert.True(t, errors.Is(err, webhook.ErrUnknownWebhook))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&calls))
+
+	atomic.StoreInt64(&status, http.StatusBadRequest)
+
+	err = d.Send(ctx, srv.URL, "taskFinished", taskFinished{Event: "task.finished", TaskID: 123})
+
+	var de *webhook.DeliveryError
+
+	require.True(t, errors.As(err, &de))
+	assert.Equal(t, http.StatusBadRequest, de.StatusCode)
+	assert.Equal(t, 1, de.Attempts)
+	assert.Equal(t, int64(3), atomic.LoadInt64(&calls))
+}
+
+func TestDispatcher_Send_retryAfter(t *testing.T) {
+	d := webhook.NewDispatcher(func(d *webhook.Dispatcher) {
+		d.Backoff = func(int) time.Duration { return time.Hour }
+	})
+	d.Add(webhook.Webhook{Name: "taskFinished", Payload: taskFinished{}})
+
+	var calls int64
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		switch atomic.AddInt64(&calls, 1) {
+		case 1:
+			rw.Header().Set("Retry-After", "0")
+			rw.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			rw.Header().Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			rw.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, d.Send(ctx, srv.URL, "taskFinished", taskFinished{Event: "task.finished", TaskID: 1}))
+	assert.Equal(t, int64(3), atomic.LoadInt64(&calls))
+	assert.Equal(t, webhook.DefaultTimeout, d.Client.Timeout)
+}
+
+func TestVerify(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"taskId":123}`)
+
+	header := func(at time.Time) http.Header {
+		h := http.Header{}
+		ts := strconv.FormatInt(at.Unix(), 10)
+
+		h.Set(webhook.HeaderID, "msg_1")
+		h.Set(webhook.HeaderTimestamp, ts)
+		h.Set(webhook.HeaderSignature, webhook.Sign(secret, "msg_1", ts, body))
+
+		return h
+	}
+
+	assert.NoError(t, webhook.Verify(secret, header(time.Now()), body, 0))
+	assert.NoError(t, webhook.Verify(secret, header(time.Now().Add(-4*time.Minute)), body, 0))
+	assert.EqualError(t, webhook.Verify(secret, header(time.Now().Add(-6*time.Minute)), body, 0),
+		"webhook timestamp is out of tolerance")
+	assert.EqualError(t, webhook.Verify(secret, header(time.Now().Add(6*time.Minute)), body, 0),
+		"webhook timestamp is out of tolerance")
+	assert.NoError(t, webhook.Verify(secret, header(time.Now().Add(-6*time.Minute)), body, time.Hour))
+
+	h := header(time.Now())
+	h.Set(webhook.HeaderTimestamp, "abc")
+	assert.EqualError(t, webhook.Verify(secret, h, body, 0), "invalid webhook timestamp")
+}
@@ -0,0 +1,2 @@
+// Package webhook describes outbound webhooks and delivers them to subscribers.
+package webhook
@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"net/http"
+)
+
+// Webhook describes an outbound request that service sends to subscribers.
+//
+// It is documented as OpenAPI 3.1 webhook or as a callback of an operation.
+type Webhook struct {
+	// Name identifies webhook, for example "task.finished".
+	Name string
+
+	// Method is an HTTP method of request, default POST.
+	Method string
+
+	Summary     string
+	Description string
+	Tags        []string
+
+	// Payload is a sample of request structure, body fields have `json` tags and headers have `header` tags.
+	Payload interface{}
+
+	// Response is an optional sample of expected response body structure.
+	Response interface{}
+}
+
+// HTTPMethod returns HTTP method of webhook request.
+func (w Webhook) HTTPMethod() string {
+	if w.Method == "" {
+		return http.MethodPost
+	}
+
+	return w.Method
+}